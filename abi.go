@@ -0,0 +1,63 @@
+package elect
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/vntchain/go-vnt/accounts/abi"
+	"github.com/vntchain/go-vnt/common"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+)
+
+var electionABI abi.ABI
+
+func init() {
+	var err error
+	if electionABI, err = abi.JSON(strings.NewReader(vntelection.AbiJSON)); err != nil {
+		panic(fmt.Sprintf("parse election abi error: %s", err))
+	}
+}
+
+// decodeInput returns the method name and the readable arguments of the input data
+// of a transaction which calls election contract.
+func decodeInput(data []byte) (string, []string, error) {
+	if len(data) < 4 {
+		return "", nil, fmt.Errorf("input data is too short: %d bytes", len(data))
+	}
+
+	method, err := electionABI.MethodById(data[:4])
+	if err != nil {
+		return "", nil, err
+	}
+	values, err := method.Inputs.UnpackValues(data[4:])
+	if err != nil {
+		return "", nil, fmt.Errorf("unpack arguments of %s error: %s", method.Name, err)
+	}
+
+	args := make([]string, len(values))
+	for i, v := range values {
+		args[i] = formatArg(v)
+	}
+	return method.Name, args, nil
+}
+
+// formatArg returns the readable string of an argument of election contract.
+func formatArg(v interface{}) string {
+	switch a := v.(type) {
+	case []byte:
+		return string(a)
+	case common.Address:
+		return a.String()
+	case []common.Address:
+		addrs := make([]string, len(a))
+		for i, addr := range a {
+			addrs[i] = addr.String()
+		}
+		return strings.Join(addrs, ",")
+	case *big.Int:
+		return a.String()
+	default:
+		return fmt.Sprintf("%v", a)
+	}
+}
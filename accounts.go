@@ -0,0 +1,97 @@
+package elect
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vntchain/go-vnt/accounts"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// AccountStatus is the election status of a configured account.
+type AccountStatus struct {
+	AccountConfig
+	Balance *Amount    `json:"balance"`
+	Stake   *Amount    `json:"stake"`
+	Voter   *rpc.Voter `json:"voter"` // nil if the account never voted
+}
+
+// UseAccount selects the configured account of the name or address for the following
+// operations, or returns an error if the account is not configured.
+func (e *Election) UseAccount(account string) error {
+	a, err := e.fileCfg.FindAccount(account)
+	if err != nil {
+		return err
+	}
+	e.setAccount(a)
+	return nil
+}
+
+// WithAccount returns a copy of Election using the configured account of the name or
+// address, e is not changed. It's used for selecting account per call.
+func (e *Election) WithAccount(account string) (*Election, error) {
+	ne := *e
+	ne.nonceLock = nil
+	ne.handleTx = nil
+	if err := ne.UseAccount(account); err != nil {
+		return nil, err
+	}
+	return &ne, nil
+}
+
+// Sender returns the address of the current account, it's empty if no account is configured.
+func (e *Election) Sender() common.Address {
+	return e.cfg.Sender
+}
+
+// Accounts returns all the configured accounts.
+func (e *Election) Accounts() []AccountConfig {
+	return e.fileCfg.AllAccounts()
+}
+
+// QueryAccounts returns the balance, stake and vote information of all the configured
+// accounts, or an error if failed.
+func (e *Election) QueryAccounts() ([]AccountStatus, error) {
+	return e.QueryAccountsContext(e.ctx)
+}
+
+// QueryAccountsContext is the same as QueryAccounts, but uses ctx for the RPC calls.
+func (e *Election) QueryAccountsContext(ctx context.Context) ([]AccountStatus, error) {
+	var ret []AccountStatus
+	for _, a := range e.Accounts() {
+		status := AccountStatus{AccountConfig: a, Stake: NewAmount(nil)}
+		status.Password = ""
+
+		balance, err := e.vc.BalanceAt(ctx, a.Address, nil)
+		if err != nil {
+			return nil, fmt.Errorf("query balance of account: %s error: %w", a.Address.String(), nodeError(err))
+		}
+		status.Balance = NewAmount(balance)
+		stake, err := e.vc.StakeAt(ctx, a.Address)
+		if err != nil && !isNotFound(err) {
+			return nil, nodeError(err)
+		} else if stake != nil {
+			status.Stake = stakeAmount(stake.StakeCount)
+		}
+		if status.Voter, err = e.vc.VoteAt(ctx, a.Address); err != nil && !isNotFound(err) {
+			return nil, nodeError(err)
+		}
+
+		ret = append(ret, status)
+	}
+	return ret, nil
+}
+
+// setAccount makes the account of a as the current account.
+func (e *Election) setAccount(a *AccountConfig) {
+	cfg := *e.cfg
+	cfg.Sender = a.Address
+	cfg.Password = a.Password
+	cfg.PasswordSource = a.PasswordSource
+	cfg.KeystoreDir = a.KeystoreDir
+	e.cfg = &cfg
+	e.account = accounts.Account{Address: a.Address}
+	e.ks = nil
+	e.wallet = nil
+}
@@ -0,0 +1,112 @@
+package elect
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Targets of alerts.
+const (
+	alertStdout = "stdout"
+	alertCmdPre = "cmd:"
+)
+
+// webhookTimeout is the timeout of posting an alert to webhook.
+const webhookTimeout = 10 * time.Second
+
+// AlertSink sends the alerts raised by watching.
+type AlertSink interface {
+	Send(ctx context.Context, a *Alert) error
+}
+
+// NewAlertSink returns the AlertSink of target, which supports:
+//
+//	stdout                       print the alert to stdout
+//	cmd:/path/to/hook arg        run the command with the alert in json from stdin
+//	https://example.com/hook     post the alert in json to the webhook URL
+func NewAlertSink(target string) (AlertSink, error) {
+	switch {
+	case target == alertStdout:
+		return writerSink{os.Stdout}, nil
+	case strings.HasPrefix(target, alertCmdPre):
+		args := strings.Fields(strings.TrimPrefix(target, alertCmdPre))
+		if len(args) == 0 {
+			return nil, errors.New("alert command is empty")
+		}
+		return cmdSink(args), nil
+	case strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://"):
+		return webhookSink(target), nil
+	default:
+		return nil, fmt.Errorf("unknown alert target: %s", target)
+	}
+}
+
+// writerSink writes the alert as a line.
+type writerSink struct {
+	w io.Writer
+}
+
+func (s writerSink) Send(ctx context.Context, a *Alert) error {
+	_, err := fmt.Fprintf(s.w, "%s block %d [%s] %s\n", a.Time.Format(time.RFC3339), a.Block, a.Rule, a.Message)
+	return err
+}
+
+// cmdSink runs the command with the alert in json from stdin, and the rule, block and
+// message in environment variables ELECT_ALERT_RULE, ELECT_ALERT_BLOCK and
+// ELECT_ALERT_MESSAGE.
+type cmdSink []string
+
+func (s cmdSink) Send(ctx context.Context, a *Alert) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s[0], s[1:]...)
+	cmd.Stdin = bytes.NewReader(data)
+	cmd.Stderr = &stderr
+	cmd.Env = append(os.Environ(),
+		"ELECT_ALERT_RULE="+a.Rule,
+		fmt.Sprintf("ELECT_ALERT_BLOCK=%d", a.Block),
+		"ELECT_ALERT_MESSAGE="+a.Message,
+	)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("run alert command %s error: %s, %s", s[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// webhookSink posts the alert in json to the URL.
+type webhookSink string
+
+func (s webhookSink) Send(ctx context.Context, a *Alert) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequest(http.MethodPost, string(s), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("post alert to webhook error: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("post alert to webhook error: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+package elect
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// AmountAll is the amount meaning the full balance minus the gas reserve.
+const AmountAll = "all"
+
+// vntDecimals is the number of decimals of VNT, 1 VNT = 1e18 wei.
+const vntDecimals = 18
+
+var weiPerVNT = big.NewInt(1e+18)
+
+// defaultGasReserve is the balance reserved for gas when staking all, 0.1 VNT.
+var defaultGasReserve = NewAmount(big.NewInt(1e+17))
+
+// Amount is an amount of VNT. It's rendered in both VNT and wei, and encoded in json
+// as {"vnt": "12.5", "wei": "12500000000000000000"}.
+type Amount struct {
+	wei *big.Int
+}
+
+// NewAmount returns the amount of wei, nil is treated as 0.
+func NewAmount(wei *big.Int) *Amount {
+	if wei == nil {
+		return &Amount{wei: big.NewInt(0)}
+	}
+	return &Amount{wei: new(big.Int).Set(wei)}
+}
+
+// ParseAmount parses the amount in VNT, such as "12.5" and "12.5vnt", or in wei, such as
+// "1000000000wei". The unit is case insensitive and default to VNT. AmountAll depends on
+// the balance, it's resolved by the operations supporting it.
+func ParseAmount(s string) (*Amount, error) {
+	v := strings.ToLower(strings.TrimSpace(s))
+	if v == AmountAll {
+		return nil, fmt.Errorf("amount %q is not supported here", s)
+	}
+
+	if strings.HasSuffix(v, "wei") {
+		wei, ok := new(big.Int).SetString(strings.TrimSpace(strings.TrimSuffix(v, "wei")), 10)
+		if !ok || wei.Sign() < 0 {
+			return nil, fmt.Errorf("invalid amount: %s", s)
+		}
+		return &Amount{wei: wei}, nil
+	}
+
+	v = strings.TrimSpace(strings.TrimSuffix(v, "vnt"))
+	intPart, fracPart := v, ""
+	if i := strings.Index(v, "."); i >= 0 {
+		intPart, fracPart = v[:i], v[i+1:]
+	}
+	if intPart == "" && fracPart == "" || len(fracPart) > vntDecimals || !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, fmt.Errorf("invalid amount: %s, at most %d decimals are allowed", s, vntDecimals)
+	}
+
+	wei, _ := new(big.Int).SetString(intPart+fracPart+strings.Repeat("0", vntDecimals-len(fracPart)), 10)
+	return &Amount{wei: wei}, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// stakeAmount returns the amount of the stake count of election contract, which is a
+// count of whole VNT, nil is treated as 0.
+func stakeAmount(count *big.Int) *Amount {
+	if count == nil {
+		return NewAmount(nil)
+	}
+	return &Amount{wei: new(big.Int).Mul(count, weiPerVNT)}
+}
+
+// Wei returns the amount in wei.
+func (a *Amount) Wei() *big.Int {
+	return new(big.Int).Set(a.wei)
+}
+
+// VNT returns the amount in VNT as a decimal string without trailing zeros.
+func (a *Amount) VNT() string {
+	abs := new(big.Int).Abs(a.wei)
+	intPart, fracPart := new(big.Int).QuoRem(abs, weiPerVNT, new(big.Int))
+
+	ret := intPart.String()
+	if frac := strings.TrimRight(fmt.Sprintf("%018s", fracPart.String()), "0"); frac != "" {
+		ret += "." + frac
+	}
+	if a.wei.Sign() < 0 {
+		ret = "-" + ret
+	}
+	return ret
+}
+
+// String returns the amount in both VNT and wei, such as "12.5 VNT (12500000000000000000 wei)".
+func (a *Amount) String() string {
+	return fmt.Sprintf("%s VNT (%s wei)", a.VNT(), a.wei.String())
+}
+
+// Cmp compares the amounts, returns -1, 0 or 1 if a is less than, equal to or greater than b.
+func (a *Amount) Cmp(b *Amount) int {
+	return a.wei.Cmp(b.wei)
+}
+
+type amountJSON struct {
+	VNT string `json:"vnt"`
+	Wei string `json:"wei"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (a *Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&amountJSON{VNT: a.VNT(), Wei: a.wei.String()})
+}
+
+// UnmarshalJSON implements json.Unmarshaler, it accepts the object encoded by MarshalJSON
+// and the string supported by ParseAmount.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		amount, err := ParseAmount(s)
+		if err != nil {
+			return err
+		}
+		*a = *amount
+		return nil
+	}
+
+	var v amountJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return fmt.Errorf("invalid amount: %s", string(data))
+	}
+	amount, err := ParseAmount(v.Wei + "wei")
+	if err != nil {
+		return err
+	}
+	*a = *amount
+	return nil
+}
@@ -0,0 +1,79 @@
+package elect
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		in  string
+		wei string
+		vnt string
+	}{
+		{"12.5", "12500000000000000000", "12.5"},
+		{"12.5vnt", "12500000000000000000", "12.5"},
+		{"1 VNT", "1000000000000000000", "1"},
+		{".5", "500000000000000000", "0.5"},
+		{"1000000000wei", "1000000000", "0.000000001"},
+		{"0.000000000000000001", "1", "0.000000000000000001"},
+	}
+	for _, test := range tests {
+		a, err := ParseAmount(test.in)
+		if err != nil {
+			t.Errorf("parse %s want no error, got: %s", test.in, err)
+			continue
+		}
+		if a.Wei().String() != test.wei || a.VNT() != test.vnt {
+			t.Errorf("parse %s want: %s wei %s VNT, got: %s wei %s VNT", test.in, test.wei, test.vnt, a.Wei(), a.VNT())
+		}
+	}
+
+	for _, in := range []string{"", ".", "-1", "1.2.3", "1e18", "0.0000000000000000001", "1.5wei", "all"} {
+		if _, err := ParseAmount(in); err == nil {
+			t.Errorf("parse %q want error, got nil", in)
+		}
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	a, _ := ParseAmount("12.5")
+	data, err := json.Marshal(a)
+	if err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+	if want := `{"vnt":"12.5","wei":"12500000000000000000"}`; string(data) != want {
+		t.Errorf("json want: %s, got: %s", want, data)
+	}
+
+	for _, in := range []string{string(data), `"12.5"`} {
+		var b Amount
+		if err := json.Unmarshal([]byte(in), &b); err != nil {
+			t.Errorf("decode %s want no error, got: %s", in, err)
+		} else if b.Cmp(a) != 0 {
+			t.Errorf("decode %s want: %s, got: %s", in, a, &b)
+		}
+	}
+}
+
+func TestStakeWholeVNT(t *testing.T) {
+	if a := stakeAmount(big.NewInt(12)); a.VNT() != "12" {
+		t.Errorf("stake count 12 want 12 VNT, got: %s VNT", a.VNT())
+	}
+
+	// the fake node has 100 VNT, all of it except the gas reserve is 99 whole VNT
+	e := newTestElection(t, newFakeCore())
+	stake := func(amount string) (*UnsignedTx, error) {
+		return e.BuildUnsignedTx(func() (common.Hash, error) { return e.Stake(amount) })
+	}
+	utx, err := stake("all")
+	if err != nil || utx.Value.Cmp(new(big.Int).Mul(big.NewInt(99), weiPerVNT)) != 0 {
+		t.Errorf("stake all want 99 VNT, got: %v, %v", utx, err)
+	}
+	if _, err := stake("1.5"); err == nil {
+		t.Errorf("stake 1.5 VNT want error, got nil")
+	}
+}
@@ -0,0 +1,150 @@
+package elect
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	hubble "github.com/vntchain/go-vnt"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/rpc"
+	"github.com/vntchain/go-vnt/vntclient"
+)
+
+// defaultRpcTimeout is the timeout of each RPC call if RpcTimeout of config is not set.
+const defaultRpcTimeout = 30 * time.Second
+
+// Backend is the client of node used by Election, it covers the methods of vntclient.Client
+// used by elect, and CallContext of rpc.Client for getting the raw transaction receipt.
+type Backend interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+	StakeAt(ctx context.Context, account common.Address) (*rpc.Stake, error)
+	VoteAt(ctx context.Context, account common.Address) (*rpc.Voter, error)
+	WitnessCandidates(ctx context.Context) ([]rpc.Candidate, error)
+	RestVNTBounty(ctx context.Context) (*big.Int, error)
+	NewElectionTx(ctx context.Context, sender common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, funcName string, args ...interface{}) (*types.Transaction, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg hubble.CallMsg) (uint64, error)
+	PendingCallContract(ctx context.Context, msg hubble.CallMsg) ([]byte, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// NewBackend returns the Backend of the node connected by rc.
+func NewBackend(rc *rpc.Client) Backend {
+	return &nodeClient{Client: vntclient.NewClient(rc), rc: rc}
+}
+
+type nodeClient struct {
+	*vntclient.Client
+	rc *rpc.Client
+}
+
+func (c *nodeClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.rc.CallContext(ctx, result, method, args...)
+}
+
+// rpcClient is the Backend used by Election, it applies the timeout to each RPC call,
+// so a hung node doesn't block the operation forever.
+type rpcClient struct {
+	b       Backend
+	timeout time.Duration
+}
+
+func newRpcClient(b Backend, timeout time.Duration) *rpcClient {
+	if timeout <= 0 {
+		timeout = defaultRpcTimeout
+	}
+	return &rpcClient{b: b, timeout: timeout}
+}
+
+func (c *rpcClient) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, c.timeout)
+}
+func (c *rpcClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.CallContext(ctx, result, method, args...)
+}
+
+func (c *rpcClient) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.BalanceAt(ctx, account, blockNumber)
+}
+
+func (c *rpcClient) StakeAt(ctx context.Context, account common.Address) (*rpc.Stake, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.StakeAt(ctx, account)
+}
+
+func (c *rpcClient) VoteAt(ctx context.Context, account common.Address) (*rpc.Voter, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.VoteAt(ctx, account)
+}
+
+func (c *rpcClient) WitnessCandidates(ctx context.Context) ([]rpc.Candidate, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.WitnessCandidates(ctx)
+}
+
+func (c *rpcClient) RestVNTBounty(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.RestVNTBounty(ctx)
+}
+
+func (c *rpcClient) NewElectionTx(ctx context.Context, sender common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, funcName string, args ...interface{}) (*types.Transaction, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.NewElectionTx(ctx, sender, value, gasLimit, gasPrice, funcName, args...)
+}
+
+func (c *rpcClient) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.SuggestGasPrice(ctx)
+}
+
+func (c *rpcClient) EstimateGas(ctx context.Context, msg hubble.CallMsg) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.EstimateGas(ctx, msg)
+}
+
+func (c *rpcClient) PendingCallContract(ctx context.Context, msg hubble.CallMsg) ([]byte, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.PendingCallContract(ctx, msg)
+}
+
+func (c *rpcClient) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.PendingNonceAt(ctx, account)
+}
+
+func (c *rpcClient) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.TransactionByHash(ctx, hash)
+}
+
+func (c *rpcClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.SendTransaction(ctx, tx)
+}
+
+func (c *rpcClient) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.b.HeaderByNumber(ctx, number)
+}
@@ -0,0 +1,40 @@
+package elect
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+func TestRpcClientTimeout(t *testing.T) {
+	done := make(chan struct{})
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer node.Close()
+	defer close(done)
+
+	rc, err := rpc.Dial(node.URL)
+	if err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+	e := &Election{cfg: &Config{}, vc: newRpcClient(NewBackend(rc), 100*time.Millisecond), ctx: context.Background()}
+
+	start := time.Now()
+	if _, err := e.QueryRestVNTBounty(); err == nil {
+		t.Errorf("want timeout error, got nil")
+	}
+	if d := time.Since(start); d > 2*time.Second {
+		t.Errorf("want timeout after 100ms, got: %s", d)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := e.QueryRestVNTBountyContext(ctx); err == nil {
+		t.Errorf("want canceled error, got nil")
+	}
+}
@@ -1,15 +1,185 @@
 package elect
 
-import "github.com/vntchain/go-vnt/common"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+// defaultAccountName is the name of the account configured by Sender.
+const defaultAccountName = "default"
 
 // Config contains accounts information and RPC information of a vnt node.
 type Config struct {
 	// Account information
-	Sender      common.Address `json:sender`
-	Password    string         `json:password`
-	KeystoreDir string         `json:keystoreDir`
+	Sender         common.Address `json:"sender"`
+	Password       string         `json:"password"`       // plaintext password, use PasswordSource instead
+	PasswordSource string         `json:"passwordSource"` // prompt, env:VAR, file:PATH or cmd:COMMAND
+	KeystoreDir    string         `json:"keystoreDir"`
+	UnlockTimeout  int            `json:"unlockTimeout"` // seconds of keeping wallet unlocked, 0 means unlock for each signing
+
+	// Named accounts, the first one is the default account if Sender is not set
+	Accounts []AccountConfig `json:"accounts"`
 
 	// Network information
-	RpcUrl  string `json:rpcUrl` // ip:port, example: localhost:8080
-	ChainID int    `json:chainID`
+	RpcUrl     string `json:"rpcUrl"` // ip:port, example: localhost:8080
+	ChainID    int    `json:"chainID"`
+	RpcTimeout int    `json:"rpcTimeout"` // seconds of timeout of each RPC call, default 30
+
+	// Gas strategy of transactions
+	Gas GasConfig `json:"gas"`
+
+	// Directory of nonce cache, default is ~/.elect/nonce
+	NonceDir string `json:"nonceDir"`
+
+	// Directory of the index of election transactions, default is ~/.elect/index/<chainID>
+	IndexDir string `json:"indexDir"`
+
+	// Directory of the payout ledgers, default is ~/.elect/payout
+	PayoutDir string `json:"payoutDir"`
+
+	// Number of witnesses of the network, default is 19
+	WitnessesNum int `json:"witnessesNum"`
+
+	// Rules and alert targets of watch mode
+	Watch WatchRules `json:"watch"`
+
+	// Seconds added to the 24 hours cooldown of unstaking, voting, setting proxy and
+	// extracting bounty, to avoid sending the transaction too early
+	CooldownMargin int `json:"cooldownMargin"`
+}
+
+// AccountConfig contains information of a named account.
+type AccountConfig struct {
+	Name           string         `json:"name"`
+	Address        common.Address `json:"address"`
+	KeystoreDir    string         `json:"keystoreDir"` // default is the keystoreDir of config
+	Password       string         `json:"password"`
+	PasswordSource string         `json:"passwordSource"`
+}
+
+// LoadConfig returns the config in the file, or an error if failed.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("Open config file error: %s\n", err)
+	}
+	defer f.Close()
+
+	config := Config{}
+	decoder := json.NewDecoder(f)
+	if err := decoder.Decode(&config); err != nil {
+		return nil, fmt.Errorf("Decode config file error: %s\n", err)
+	}
+
+	// 包含明文密码的配置文件不可被其他用户读取
+	if info, err := f.Stat(); err == nil && runtime.GOOS != "windows" {
+		if config.hasPassword() && info.Mode().Perm()&0004 != 0 {
+			return nil, fmt.Errorf("Config file %s contains password and is world-readable, please chmod o-r or use passwordSource\n", path)
+		}
+	}
+	return &config, nil
+}
+
+// Masked returns a copy of config with the passwords masked, it's used for displaying.
+func (c *Config) Masked() *Config {
+	mask := func(s string) string {
+		if s == "" {
+			return ""
+		}
+		return "******"
+	}
+
+	mc := *c
+	mc.Password = mask(c.Password)
+	mc.Accounts = make([]AccountConfig, len(c.Accounts))
+	for i, a := range c.Accounts {
+		a.Password = mask(a.Password)
+		mc.Accounts[i] = a
+	}
+	return &mc
+}
+
+// AllAccounts returns all the configured accounts. The account of Sender is named
+// "default" if it's not one of the named accounts.
+func (c *Config) AllAccounts() []AccountConfig {
+	var all []AccountConfig
+	named := false
+	for _, a := range c.Accounts {
+		if a.KeystoreDir == "" {
+			a.KeystoreDir = c.KeystoreDir
+		}
+		if a.Address == c.Sender {
+			named = true
+		}
+		all = append(all, a)
+	}
+
+	if c.Sender != (common.Address{}) && !named {
+		def := AccountConfig{
+			Name:           defaultAccountName,
+			Address:        c.Sender,
+			KeystoreDir:    c.KeystoreDir,
+			Password:       c.Password,
+			PasswordSource: c.PasswordSource,
+		}
+		all = append([]AccountConfig{def}, all...)
+	}
+	return all
+}
+
+// FindAccount returns the configured account of the name or address, or an error if
+// not found.
+func (c *Config) FindAccount(account string) (*AccountConfig, error) {
+	for _, a := range c.AllAccounts() {
+		if a.Name == account {
+			return &a, nil
+		}
+		if common.IsHexAddress(account) && a.Address == common.HexToAddress(account) {
+			return &a, nil
+		}
+	}
+	return nil, fmt.Errorf("account %s is not configured, configured accounts: %s", account, strings.Join(c.accountNames(), ", "))
+}
+
+// defaultAccount returns the account of Sender, or the first named account if
+// Sender is not set, or nil if no account is configured.
+func (c *Config) defaultAccount() *AccountConfig {
+	all := c.AllAccounts()
+	if len(all) == 0 {
+		return nil
+	}
+	if c.Sender != (common.Address{}) {
+		for _, a := range all {
+			if a.Address == c.Sender {
+				return &a
+			}
+		}
+	}
+	return &all[0]
+}
+
+// hasPassword returns true if any plaintext password is in config.
+func (c *Config) hasPassword() bool {
+	if c.Password != "" {
+		return true
+	}
+	for _, a := range c.Accounts {
+		if a.Password != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Config) accountNames() []string {
+	var names []string
+	for _, a := range c.AllAccounts() {
+		names = append(names, a.Name)
+	}
+	return names
 }
@@ -0,0 +1,41 @@
+package elect
+
+import (
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestConfigFindAccount(t *testing.T) {
+	cfg := &Config{
+		Sender:      common.HexToAddress("0x122369f04f32269598789998de33e3d56e2c507a"),
+		KeystoreDir: "./tests/keystore",
+		Accounts: []AccountConfig{
+			{Name: "witness", Address: common.HexToAddress("0x3dcf0b3787c31b2bdf62d5bc9128a79c2bb18829")},
+		},
+	}
+
+	if n := len(cfg.AllAccounts()); n != 2 {
+		t.Fatalf("accounts number want: 2, got: %d", n)
+	}
+
+	a, err := cfg.FindAccount("witness")
+	if err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+	if a.KeystoreDir != cfg.KeystoreDir {
+		t.Errorf("keystore dir want: %s, got: %s", cfg.KeystoreDir, a.KeystoreDir)
+	}
+
+	a, err = cfg.FindAccount("0x122369f04f32269598789998de33e3d56e2c507a")
+	if err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+	if a.Name != defaultAccountName {
+		t.Errorf("account name want: %s, got: %s", defaultAccountName, a.Name)
+	}
+
+	if _, err := cfg.FindAccount("voter"); err == nil {
+		t.Errorf("want error of account not configured, got nil")
+	}
+}
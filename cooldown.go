@@ -0,0 +1,52 @@
+package elect
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+)
+
+// ChainTime returns the time of the latest block, or an error if failed.
+func (e *Election) ChainTime() (time.Time, error) {
+	return e.ChainTimeContext(e.ctx)
+}
+
+// ChainTimeContext is the same as ChainTime, but uses ctx for the RPC calls.
+func (e *Election) ChainTimeContext(ctx context.Context) (time.Time, error) {
+	return e.chainTime(ctx)
+}
+
+// chainTime returns the time of the latest block. The contract checks the cooldown against
+// the time of the block including the transaction, which is not earlier than it, so the
+// checks don't depend on the local clock.
+func (e *Election) chainTime(ctx context.Context) (time.Time, error) {
+	head, err := e.vc.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return time.Time{}, nodeError(err)
+	}
+	return time.Unix(head.Time.Int64(), 0), nil
+}
+
+// cooldown returns the time when the operation done at the timestamp last becomes possible,
+// including the safety margin of config, and the remaining duration from the chain time.
+// The operation is possible now if remaining is not positive.
+func (e *Election) cooldown(ctx context.Context, last *big.Int) (time.Time, time.Duration, error) {
+	now, err := e.chainTime(ctx)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	next := e.nextAllowed(last)
+	return next, next.Sub(now), nil
+}
+
+// nextAllowed returns the time when the operation done at the timestamp last becomes
+// possible, including the safety margin of config.
+func (e *Election) nextAllowed(last *big.Int) time.Time {
+	next := time.Unix(0, 0)
+	if last != nil {
+		next = time.Unix(last.Int64()+vntelection.OneDay, 0)
+	}
+	return next.Add(time.Duration(e.cfg.CooldownMargin) * time.Second)
+}
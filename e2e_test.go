@@ -0,0 +1,302 @@
+package elect_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vntchain/elect"
+	"github.com/vntchain/elect/electtest"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
+	"github.com/vntchain/go-vnt/vntclient"
+)
+
+const (
+	testChainID = 2
+	testNodeUrl = "/ip4/127.0.0.1/tcp/3001/ipfs/1kHaMUmZgTpjGEhxcGATr1UVWy4iKkygFuknWEtW1hiZXKt"
+)
+
+// testElection returns a Election of the account connected to the fake node.
+func testElection(t *testing.T, node *electtest.Node, a *electtest.Account, nonceDir string) *elect.Election {
+	cfg := &elect.Config{Sender: a.Address, ChainID: testChainID, NonceDir: nonceDir, PayoutDir: nonceDir}
+	e, err := elect.NewElectionWithConfig(cfg, elect.WithClient(elect.NewBackend(node.Dial())),
+		elect.WithSigner(a), elect.WithClock(node.Now))
+	if err != nil {
+		t.Fatalf("new election error: %s", err)
+	}
+	return e
+}
+
+// mustSucceed waits the transaction sent by op mined, and fails the test if op or the
+// transaction failed.
+func mustSucceed(t *testing.T, e *elect.Election, name string, op elect.Op) {
+	t.Helper()
+	hash, err := op()
+	if err != nil {
+		t.Fatalf("%s error: %s", name, err)
+	}
+	ret, err := e.WaitMined(context.Background(), hash)
+	if err != nil {
+		t.Fatalf("wait %s mined error: %s", name, err)
+	}
+	if !ret.Success {
+		t.Fatalf("%s transaction %s failed", name, hash.String())
+	}
+}
+
+// voter queries the vote information of the account from node.
+func voter(t *testing.T, vc *vntclient.Client, addr common.Address) *rpc.Voter {
+	t.Helper()
+	v, err := vc.VoteAt(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("query voter %s error: %s", addr.String(), err)
+	}
+	return v
+}
+
+// candidateVotes queries the vote count of the candidate from node.
+func candidateVotes(t *testing.T, vc *vntclient.Client, addr common.Address) *big.Int {
+	t.Helper()
+	candidates, err := vc.WitnessCandidates(context.Background())
+	if err != nil {
+		t.Fatalf("query candidates error: %s", err)
+	}
+	for _, c := range candidates {
+		if c.Owner == addr.String() {
+			return c.VoteCount.ToInt()
+		}
+	}
+	t.Fatalf("candidate %s not found", addr.String())
+	return nil
+}
+
+// queryStake queries the stake of the account by QueryStake.
+func queryStake(t *testing.T, e *elect.Election) *elect.Amount {
+	t.Helper()
+	data, err := e.QueryStake()
+	if err != nil {
+		t.Fatalf("query stake error: %s", err)
+	}
+	var stake struct {
+		StakeCount *elect.Amount `json:"stakeCount"`
+	}
+	if err := json.Unmarshal(data, &stake); err != nil {
+		t.Fatalf("decode stake %s error: %s", data, err)
+	}
+	return stake.StakeCount
+}
+
+func TestElectionEndToEnd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	vc := vntclient.NewClient(node.Dial())
+
+	witnessAcc, voterAcc, proxyAcc := electtest.NewAccount(), electtest.NewAccount(), electtest.NewAccount()
+	for _, a := range []*electtest.Account{witnessAcc, voterAcc, proxyAcc} {
+		node.Fund(a.Address, electtest.VNT(100))
+	}
+	witness := testElection(t, node, witnessAcc, dir)
+	voterE := testElection(t, node, voterAcc, dir)
+	proxy := testElection(t, node, proxyAcc, dir)
+
+	// register witness
+	mustSucceed(t, witness, "register", func() (common.Hash, error) {
+		return witness.RegisterWitness("node1", testNodeUrl, "www.node1.com")
+	})
+	if _, err := witness.RegisterWitness("node1", testNodeUrl, "www.node1.com"); err == nil {
+		t.Errorf("register twice want error, got nil")
+	}
+
+	// stake and vote
+	if _, err := voterE.Vote([]string{witnessAcc.Address.String()}); !errors.Is(err, elect.ErrNoStake) {
+		t.Errorf("vote without stake want ErrNoStake, got: %v", err)
+	}
+	mustSucceed(t, voterE, "stake", func() (common.Hash, error) { return voterE.Stake("10") })
+	mustSucceed(t, voterE, "vote", func() (common.Hash, error) {
+		return voterE.Vote([]string{witnessAcc.Address.String()})
+	})
+	v := voter(t, vc, voterAcc.Address)
+	if len(v.VoteCandidates) != 1 || v.VoteCandidates[0] != witnessAcc.Address {
+		t.Errorf("want voted witness %s, got: %v", witnessAcc.Address.String(), v.VoteCandidates)
+	}
+	if votes := candidateVotes(t, vc, witnessAcc.Address); votes.Sign() <= 0 || votes.Cmp(v.LastVoteCount) != 0 {
+		t.Errorf("want witness votes %s, got: %s", v.LastVoteCount, votes)
+	}
+	status, err := voterE.Status()
+	if err != nil {
+		t.Fatalf("query status error: %s", err)
+	}
+	if status.Stake.Wei().Cmp(electtest.VNT(10)) != 0 {
+		t.Errorf("want stake 10 VNT, got: %s", status.Stake)
+	}
+	if status.VoteWeight.Cmp(v.LastVoteCount) != 0 || len(status.VotedCandidates) != 1 || status.VotedCandidates[0].Rank != 1 {
+		t.Errorf("want vote weight %s and the voted witness ranked 1, got: %s, %+v", v.LastVoteCount, status.VoteWeight, status.VotedCandidates)
+	}
+	if status.NextVoteAllowed == nil || !status.NextVoteAllowed.Equal(node.Now().Add(24*time.Hour)) {
+		t.Errorf("want next vote allowed after 24 hours, got: %v", status.NextVoteAllowed)
+	}
+	if status, err := voterE.StatusOf(witnessAcc.Address); err != nil || status.Candidate == nil || status.Candidate.Rank != 1 {
+		t.Errorf("want the witness ranked 1, got: %+v, %v", status, err)
+	}
+	if w, err := voterE.QueryVoteWeight(); err != nil || w.Revote.Cmp(v.LastVoteCount) != 0 || w.Increase.Sign() != 0 {
+		t.Errorf("want revote weight %s without increase, got: %+v, %v", v.LastVoteCount, w, err)
+	}
+	var cooldown *elect.ErrVoteCooldown
+	if _, err := voterE.Vote([]string{witnessAcc.Address.String()}); !errors.As(err, &cooldown) {
+		t.Errorf("vote twice want ErrVoteCooldown, got: %v", err)
+	}
+
+	// vote by proxy
+	mustSucceed(t, proxy, "start proxy", proxy.StartProxy)
+	mustSucceed(t, proxy, "stake", func() (common.Hash, error) { return proxy.Stake("20") })
+	mustSucceed(t, proxy, "vote", func() (common.Hash, error) {
+		return proxy.Vote([]string{witnessAcc.Address.String()})
+	})
+	// the cooldown is checked against the latest block, not the local clock
+	node.AdvanceTime(25 * time.Hour)
+	if _, err := voterE.SetProxy(proxyAcc.Address.String()); !errors.As(err, &cooldown) || cooldown.Remaining <= 0 {
+		t.Errorf("set proxy before a new block want ErrVoteCooldown, got: %v", err)
+	}
+	node.Mine()
+	mustSucceed(t, voterE, "set proxy", func() (common.Hash, error) { return voterE.SetProxy(proxyAcc.Address.String()) })
+	v, p := voter(t, vc, voterAcc.Address), voter(t, vc, proxyAcc.Address)
+	if v.Proxy != proxyAcc.Address || p.ProxyVoteCount.Cmp(v.LastVoteCount) != 0 {
+		t.Errorf("want proxy %s with proxy votes %s, got: %s, %s", proxyAcc.Address.String(), v.LastVoteCount, v.Proxy.String(), p.ProxyVoteCount)
+	}
+	want := new(big.Int).Add(p.LastVoteCount, p.ProxyVoteCount)
+	if votes := candidateVotes(t, vc, witnessAcc.Address); votes.Cmp(want) != 0 {
+		t.Errorf("want witness votes %s, got: %s", want, votes)
+	}
+	mustSucceed(t, voterE, "cancel proxy", voterE.CancelProxy)
+	if votes := candidateVotes(t, vc, witnessAcc.Address); votes.Cmp(p.LastVoteCount) != 0 {
+		t.Errorf("want witness votes %s after canceling proxy, got: %s", p.LastVoteCount, votes)
+	}
+
+	// unstake
+	before := node.Balance(voterAcc.Address)
+	mustSucceed(t, voterE, "unstake", voterE.Unstake)
+	if node.Balance(voterAcc.Address).Cmp(before) <= 0 {
+		t.Errorf("want balance increased after unstaking, got: %s, before: %s", node.Balance(voterAcc.Address), before)
+	}
+	if _, err := voterE.Unstake(); !errors.Is(err, elect.ErrNoStake) {
+		t.Errorf("unstake twice want ErrNoStake, got: %v", err)
+	}
+
+	// extract bounty
+	if _, err := witness.ExtractBounty(); !errors.Is(err, elect.ErrBountyNotEnough) {
+		t.Errorf("extract without bounty want ErrBountyNotEnough, got: %v", err)
+	}
+	if err := node.GrantBounty(witnessAcc.Address, electtest.VNT(500)); err != nil {
+		t.Fatalf("grant bounty error: %s", err)
+	}
+	if status, err := witness.Status(); err != nil || status.Candidate == nil || status.Candidate.ExtractableBounty.Wei().Sign() != 0 {
+		t.Errorf("want no extractable bounty less than 1000 VNT, got: %+v, %v", status, err)
+	}
+	if err := node.GrantBounty(witnessAcc.Address, electtest.VNT(1500)); err != nil {
+		t.Fatalf("grant bounty error: %s", err)
+	}
+	bounty, err := witness.QueryExtractableBounty()
+	if err != nil || bounty.Wei().Cmp(electtest.VNT(2000)) != 0 {
+		t.Fatalf("want extractable bounty 2000 VNT, got: %v, %v", bounty, err)
+	}
+	before = node.Balance(witnessAcc.Address)
+	mustSucceed(t, witness, "extract bounty", witness.ExtractBounty)
+	if gain := new(big.Int).Sub(node.Balance(witnessAcc.Address), before); gain.Cmp(electtest.VNT(1999)) < 0 {
+		t.Errorf("want balance increased by the bounty, got: %s", gain)
+	}
+	var extractCooldown *elect.ErrExtractCooldown
+	if _, err := witness.ExtractBounty(); !errors.As(err, &extractCooldown) {
+		t.Errorf("extract twice want ErrExtractCooldown, got: %v", err)
+	}
+
+	// unregister witness
+	mustSucceed(t, witness, "unregister", witness.UnregisterWitness)
+	if _, err := witness.UnregisterWitness(); !errors.Is(err, elect.ErrNotCandidate) {
+		t.Errorf("unregister twice want ErrNotCandidate, got: %v", err)
+	}
+}
+
+func TestStakeCountInVNT(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	acc, other := electtest.NewAccount(), electtest.NewAccount()
+	node.Fund(acc.Address, electtest.VNT(100))
+	node.Fund(other.Address, new(big.Int).Add(electtest.VNT(50), big.NewInt(5e+17)))
+	e := testElection(t, node, acc, dir)
+
+	if _, err := e.Stake("1.5"); !errors.Is(err, elect.ErrStakeNotWhole) {
+		t.Errorf("stake 1.5 VNT want ErrStakeNotWhole, got: %v", err)
+	}
+	mustSucceed(t, e, "stake", func() (common.Hash, error) { return e.Stake("10") })
+
+	// the stake count of contract is displayed in VNT
+	if stake := queryStake(t, e); stake.Wei().Cmp(electtest.VNT(10)) != 0 {
+		t.Errorf("want stake 10 VNT, got: %s", stake)
+	}
+	accounts, err := e.QueryAccounts()
+	if err != nil || len(accounts) != 1 || accounts[0].Stake.Wei().Cmp(electtest.VNT(10)) != 0 {
+		t.Errorf("want the account staked 10 VNT, got: %+v, %v", accounts, err)
+	}
+
+	// staking all rounds the balance minus the gas reserve down to whole VNT
+	all := testElection(t, node, other, dir)
+	mustSucceed(t, all, "stake all", func() (common.Hash, error) { return all.Stake(elect.AmountAll) })
+	if stake := queryStake(t, all); stake.Wei().Cmp(electtest.VNT(50)) != 0 {
+		t.Errorf("want stake all of 50.5 VNT staked 50 VNT, got: %s", stake)
+	}
+}
+
+func TestWatchOnlyElection(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	acc := electtest.NewAccount()
+	node.Fund(acc.Address, electtest.VNT(100))
+	staker := testElection(t, node, acc, dir)
+	mustSucceed(t, staker, "stake", func() (common.Hash, error) { return staker.Stake("10") })
+
+	if _, err := elect.NewWatchOnlyElection(&elect.Config{}); err == nil {
+		t.Errorf("watch-only election without node want error, got nil")
+	}
+	cfg := &elect.Config{Sender: acc.Address, ChainID: testChainID, NonceDir: dir}
+	watch, err := elect.NewWatchOnlyElection(cfg, elect.WithClient(elect.NewBackend(node.Dial())))
+	if err != nil {
+		t.Fatalf("new watch-only election error: %s", err)
+	}
+	if _, err := watch.QueryStakeOf(acc.Address); err != nil {
+		t.Errorf("query stake of %s error: %s", acc.Address.String(), err)
+	}
+	other := electtest.NewAccount().Address
+	if _, err := watch.QueryStakeOf(other); !errors.Is(err, elect.ErrNoStake) {
+		t.Errorf("query stake of account without stake want ErrNoStake, got: %v", err)
+	}
+	if _, err := watch.QueryVoteOf(acc.Address); !errors.Is(err, elect.ErrNotVoted) {
+		t.Errorf("query vote of account not voted want ErrNotVoted, got: %v", err)
+	}
+	if _, err := watch.Stake("1"); !errors.Is(err, elect.ErrWatchOnly) {
+		t.Errorf("stake by watch-only election want ErrWatchOnly, got: %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+var accountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "List configured accounts",
+	Long: `Accounts lists the accounts configured in config file, with the balance,
+stake and vote status of each account.`,
+	Example: `elect accounts`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+
+		e := newElection()
+		accounts, err := e.QueryAccountsContext(cmdCtx)
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+
+		for _, a := range accounts {
+			info("%s\t%s\tbalance: %s\tstake: %s\tvote: %s\n",
+				a.Name, a.Address.String(), a.Balance, a.Stake, voteStatus(a.Voter))
+		}
+		printResult(&accountsOutput{Command: command, Accounts: accounts})
+	},
+}
+
+// accountsOutput is the result schema of accounts command.
+type accountsOutput struct {
+	Command  string                `json:"command"`
+	Accounts []elect.AccountStatus `json:"accounts"`
+}
+
+// voteStatus returns a short description of the vote information.
+func voteStatus(voter *rpc.Voter) string {
+	switch {
+	case voter == nil:
+		return "not voted"
+	case voter.IsProxy:
+		return fmt.Sprintf("vote proxy, voted %d candidates", len(voter.VoteCandidates))
+	case voter.Proxy != (common.Address{}):
+		return fmt.Sprintf("vote by proxy %s", voter.Proxy.String())
+	default:
+		return fmt.Sprintf("voted %d candidates", len(voter.VoteCandidates))
+	}
+}
@@ -0,0 +1,193 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+)
+
+// Sort keys of the candidates command.
+const (
+	sortRank   = "rank"
+	sortName   = "name"
+	sortShare  = "share"
+	sortBounty = "bounty"
+)
+
+var (
+	candidatesActive    bool
+	candidatesName      string
+	candidatesSort      string
+	candidatesReverse   bool
+	candidatesOffset    int
+	candidatesLimit     int
+	candidatesWitnesses int
+)
+
+var candidatesCmd = &cobra.Command{
+	Use:   "candidates",
+	Short: "Show the ranking of witness candidates",
+	Long: `Candidates shows the witness candidates sorted the same as the election
+contract: more votes first, then the smaller address first, inactive candidates
+are last. The candidates in the top witnesses selected by the contract are
+marked, with the vote share of each candidate and the votes separating it from
+the cutoff: a witness leads the first candidate out of witnesses by the gap,
+and the others are behind the last witness by the gap.`,
+	Example: `elect candidates
+elect candidates --active --name node --limit 10
+elect candidates --sort bounty --reverse --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+		if candidatesOffset < 0 || candidatesLimit < 0 {
+			fail(exitUsage, fmt.Errorf("offset and limit should not be negative"))
+		}
+
+		e := newWatchElection()
+		if candidatesWitnesses > 0 {
+			e.SetWitnessesNum(candidatesWitnesses)
+		}
+		r, err := e.RankCandidatesContext(cmdCtx)
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+
+		list := filterCandidates(r.Candidates)
+		if err := sortRanked(list, candidatesSort, candidatesReverse); err != nil {
+			fail(exitUsage, err)
+		}
+		total := len(list)
+		list = paginate(list, candidatesOffset, candidatesLimit)
+
+		printCandidates(r, list, total)
+		printResult(&candidatesOutput{
+			Command:      command,
+			WitnessesNum: r.WitnessesNum,
+			Selected:     r.Selected,
+			TotalVotes:   r.TotalVotes,
+			Cutoff:       r.Cutoff,
+			Total:        total,
+			Candidates:   list,
+		})
+	},
+}
+
+// candidatesOutput is the result schema of candidates command, total is the number of
+// candidates matched the filters before pagination.
+type candidatesOutput struct {
+	Command      string                  `json:"command"`
+	WitnessesNum int                     `json:"witnessesNum"`
+	Selected     bool                    `json:"selected"`
+	TotalVotes   *big.Int                `json:"totalVotes"`
+	Cutoff       *big.Int                `json:"cutoff"`
+	Total        int                     `json:"total"`
+	Candidates   []elect.RankedCandidate `json:"candidates"`
+}
+
+func init() {
+	candidatesCmd.Flags().BoolVar(&candidatesActive, "active", false, "show the active candidates only")
+	candidatesCmd.Flags().StringVar(&candidatesName, "name", "", "show the candidates whose name contains the string, case insensitive")
+	candidatesCmd.Flags().StringVar(&candidatesSort, "sort", sortRank, "sort by rank, name, share or bounty, bounty is the rest bounty not extracted")
+	candidatesCmd.Flags().BoolVar(&candidatesReverse, "reverse", false, "reverse the order")
+	candidatesCmd.Flags().IntVar(&candidatesOffset, "offset", 0, "skip the first candidates")
+	candidatesCmd.Flags().IntVar(&candidatesLimit, "limit", 0, "show at most the number of candidates, 0 means no limit")
+	candidatesCmd.Flags().IntVar(&candidatesWitnesses, "witnesses", 0, "number of witnesses of the network, overrides witnessesNum of config, default 19")
+}
+
+// filterCandidates returns the candidates matched --active and --name.
+func filterCandidates(candidates []elect.RankedCandidate) []elect.RankedCandidate {
+	name := strings.ToLower(candidatesName)
+	var ret []elect.RankedCandidate
+	for _, c := range candidates {
+		if candidatesActive && !c.Active {
+			continue
+		}
+		if name != "" && !strings.Contains(strings.ToLower(c.Name), name) {
+			continue
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// sortRanked sorts the candidates by the key, the ties are kept in rank order.
+func sortRanked(list []elect.RankedCandidate, key string, reverse bool) error {
+	var less func(a, b *elect.RankedCandidate) bool
+	switch key {
+	case sortRank:
+		less = func(a, b *elect.RankedCandidate) bool { return a.Rank < b.Rank }
+	case sortName:
+		less = func(a, b *elect.RankedCandidate) bool { return a.Name < b.Name }
+	case sortShare:
+		less = func(a, b *elect.RankedCandidate) bool { return a.Share > b.Share }
+	case sortBounty:
+		less = func(a, b *elect.RankedCandidate) bool { return restBounty(a).Cmp(restBounty(b)) > 0 }
+	default:
+		return fmt.Errorf("invalid sort key: %s, should be one of rank, name, share and bounty", key)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if reverse {
+			return less(&list[j], &list[i])
+		}
+		return less(&list[i], &list[j])
+	})
+	return nil
+}
+
+// restBounty returns the bounty not extracted of the candidate.
+func restBounty(c *elect.RankedCandidate) *elect.Amount {
+	return elect.NewAmount(new(big.Int).Sub(c.TotalBounty.Wei(), c.ExtractedBounty.Wei()))
+}
+
+// paginate returns the page of list, limit 0 means no limit.
+func paginate(list []elect.RankedCandidate, offset, limit int) []elect.RankedCandidate {
+	if offset >= len(list) {
+		return nil
+	}
+	list = list[offset:]
+	if limit > 0 && limit < len(list) {
+		list = list[:limit]
+	}
+	return list
+}
+
+// printCandidates prints the ranking in text format.
+func printCandidates(r *elect.Ranking, list []elect.RankedCandidate, total int) {
+	if output != outputText {
+		return
+	}
+	if r.Selected {
+		fmt.Printf("witnesses: %d, cutoff: %s votes, total votes: %s\n", r.WitnessesNum, r.Cutoff, r.TotalVotes)
+	} else {
+		fmt.Printf("witnesses: %d, valid candidates are not enough, no witness is selected, total votes: %s\n", r.WitnessesNum, r.TotalVotes)
+	}
+	fmt.Printf("showing %d of %d candidates\n\n", len(list), total)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "RANK\tNAME\tADDRESS\tACTIVE\tWITNESS\tVOTES\tSHARE\tGAP\tBOUNTY")
+	for i := range list {
+		c := &list[i]
+		witness := ""
+		if c.Witness {
+			witness = "*"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%t\t%s\t%s\t%.2f%%\t%s\t%s\n", c.Rank, c.Name, c.Address.String(),
+			c.Active, witness, c.VoteCount, c.Share, bigString(c.CutoffGap), restBounty(c).VNT())
+	}
+	tw.Flush()
+}
+
+// bigString returns the decimal string of the number, or "-" if it's nil.
+func bigString(v *big.Int) string {
+	if v == nil {
+		return "-"
+	}
+	return v.String()
+}
@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+)
+
+// Environment variables of config.
+const (
+	envConfig   = "ELECT_CONFIG"
+	envRpc      = "ELECT_RPC"
+	envChainID  = "ELECT_CHAIN_ID"
+	envSender   = "ELECT_SENDER"
+	envKeystore = "ELECT_KEYSTORE"
+)
+
+var (
+	cfgFile     string
+	rpcUrl      string
+	rpcTimeout  time.Duration
+	chainID     string
+	sender      string
+	keystoreDir string
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage config of elect",
+	Long: `Config shows the config used by elect. The config file is searched in
+--config flag, ELECT_CONFIG environment variable, ./config.json,
+$XDG_CONFIG_HOME/elect/config.json and ~/.elect/config.json in order.`,
+	Example: `elect config show`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:     "show",
+	Short:   "Show the effective config",
+	Long:    `Show prints the config merged from config file, flags and environment variables, passwords are masked.`,
+	Example: `elect config show --rpc http://localhost:8880`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path, cfg := loadConfig()
+		if output != outputText {
+			printResult(&configOutput{Command: command, File: path, Config: cfg.Masked()})
+			return
+		}
+		data, err := json.MarshalIndent(cfg.Masked(), "", "    ")
+		if err != nil {
+			fail(exitError, err)
+		}
+		fmt.Printf("config file: %s\n%s\n", path, string(data))
+	},
+}
+
+// configOutput is the result schema of config show command.
+type configOutput struct {
+	Command string        `json:"command"`
+	File    string        `json:"file"`
+	Config  *elect.Config `json:"config"`
+}
+
+func init() {
+	configCmd.AddCommand(configShowCmd)
+}
+
+// configPath returns the path of config file found in flag, environment variable and
+// search path, or an error if not found.
+func configPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if path := os.Getenv(envConfig); path != "" {
+		return path, nil
+	}
+
+	paths := []string{"./config.json"}
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		paths = append(paths, filepath.Join(xdg, "elect", "config.json"))
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(home, ".elect", "config.json"))
+	}
+	for _, path := range paths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("config file is not found in: %v, use --config to set it", paths)
+}
+
+// loadConfig returns the path of config file and the config overridden by flags and
+// environment variables, flags take precedence over environment variables.
+func loadConfig() (string, *elect.Config) {
+	path, err := configPath()
+	if err != nil {
+		fail(exitConfig, err)
+	}
+	cfg, err := elect.LoadConfig(path)
+	if err != nil {
+		fail(exitConfig, err)
+	}
+	overrideConfig(cfg)
+	return path, cfg
+}
+
+// loadWatchConfig is the same as loadConfig, but the config file is optional if the RPC
+// URL is set by flag or environment variable, it's used by the commands only querying.
+func loadWatchConfig() *elect.Config {
+	if _, err := configPath(); err != nil && override(rpcUrl, envRpc) != "" {
+		cfg := &elect.Config{}
+		overrideConfig(cfg)
+		return cfg
+	}
+	_, cfg := loadConfig()
+	return cfg
+}
+
+// overrideConfig overrides cfg by flags and environment variables.
+func overrideConfig(cfg *elect.Config) {
+	if v := override(rpcUrl, envRpc); v != "" {
+		cfg.RpcUrl = v
+	}
+	if v := override(chainID, envChainID); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			fail(exitConfig, fmt.Errorf("invalid chain id: %s", v))
+		}
+		cfg.ChainID = id
+	}
+	if v := override(sender, envSender); v != "" {
+		if !common.IsHexAddress(v) {
+			fail(exitConfig, fmt.Errorf("invalid sender address: %s", v))
+		}
+		cfg.Sender = common.HexToAddress(v)
+	}
+	if v := override(keystoreDir, envKeystore); v != "" {
+		cfg.KeystoreDir = v
+	}
+	if rpcTimeout > 0 {
+		cfg.RpcTimeout = int(math.Ceil(rpcTimeout.Seconds()))
+	}
+}
+
+// override returns the value of flag, or the value of environment variable if the
+// flag is not set.
+func override(flag, env string) string {
+	if flag != "" {
+		return flag
+	}
+	return os.Getenv(env)
+}
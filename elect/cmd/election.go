@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/vntchain/elect"
+)
+
+var (
+	gasPrice string
+	gasLimit uint64
+	account  string
+)
+
+// newElection returns a Election with the global flags applied.
+func newElection() *elect.Election {
+	_, cfg := loadConfig()
+	e, err := elect.NewElectionWithConfig(cfg)
+	if err != nil {
+		fail(exitConfig, err)
+	}
+	applyFlags(e)
+
+	if gasPrice != "" {
+		price, ok := big.NewInt(0).SetString(gasPrice, 10)
+		if !ok {
+			fail(exitUsage, fmt.Errorf("invalid gas price: %s", gasPrice))
+		}
+		e.SetGasPrice(price)
+	}
+	if gasLimit > 0 {
+		e.SetGasLimit(gasLimit)
+	}
+	return e
+}
+
+// newOfflineElection returns a Election doesn't connect to node with the global
+// flags applied.
+func newOfflineElection() *elect.Election {
+	_, cfg := loadConfig()
+	cfg.RpcUrl = ""
+	e, err := elect.NewElectionWithConfig(cfg)
+	if err != nil {
+		fail(exitConfig, err)
+	}
+	applyFlags(e)
+	return e
+}
+
+// newWatchElection returns a watch-only Election with the global flags applied, it
+// requires no keystore and can only query.
+func newWatchElection() *elect.Election {
+	e, err := elect.NewWatchOnlyElection(loadWatchConfig())
+	if err != nil {
+		fail(exitConfig, err)
+	}
+	applyFlags(e)
+	return e
+}
+
+// applyFlags applies the global flags of account.
+func applyFlags(e *elect.Election) {
+	if account != "" {
+		if err := e.UseAccount(account); err != nil {
+			fail(exitConfig, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+)
+
+var (
+	indexDir    string
+	indexFrom   uint64
+	indexAddr   string
+	indexMethod string
+	indexLimit  int
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Index the transactions of election contract",
+	Long: `Index scans the blocks from the node and saves the transactions calling the
+election contract into a local database, with the decoded method, arguments
+and result. It continues from the last indexed block, and reverts the blocks
+reorganized by the chain, so it can be run again at any time. It indexes
+from the block of --from at the first run. It runs until the latest block is
+indexed, only limited by an explicitly set --timeout.
+
+The index is used by the commands of the election history, list shows the
+indexed transactions.`,
+	Example: `elect index
+elect index --from 1200000 --timeout 1h
+elect index list --method voteWitnesses --limit 20`,
+	Annotations: map[string]string{annotationNoDeadline: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+		ix := openIndex(newWatchElection())
+		defer ix.Close()
+
+		last := time.Now()
+		ret, err := ix.Sync(cmdCtx, indexFrom, func(number, latest uint64) {
+			if time.Since(last) >= time.Second || number == latest {
+				info("indexed block %d of %d\n", number, latest)
+				last = time.Now()
+			}
+		})
+		if ret != nil {
+			if ret.Reverted > 0 {
+				info("reverted %d reorganized blocks\n", ret.Reverted)
+			}
+			info("indexed %d blocks, %d election transactions\n", ret.Blocks, ret.Txs)
+		}
+		if err != nil {
+			fail(exitCode(err), fmt.Errorf("%s, run index again to continue", err))
+		}
+		printResult(&indexOutput{Command: command, SyncResult: ret})
+	},
+}
+
+var indexListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the indexed transactions",
+	Long: `List shows the indexed transactions of election contract, the latest first.
+Only the indexed blocks are listed, run index first to index the latest blocks.`,
+	Example: `elect index list
+elect index list --address 0x122369f04f32269598789998de33e3d56e2c507a --method voteWitnesses,setProxy`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+		filter := &elect.TxFilter{FromBlock: indexFrom}
+		if indexAddr != "" {
+			if !common.IsHexAddress(indexAddr) {
+				fail(exitUsage, fmt.Errorf("invalid address: %s", indexAddr))
+			}
+			addr := common.HexToAddress(indexAddr)
+			filter.From = &addr
+		}
+		if indexMethod != "" {
+			filter.Methods = strings.Split(indexMethod, ",")
+		}
+
+		ix := openIndex(newWatchElection())
+		defer ix.Close()
+		txs, err := ix.Txs(filter)
+		if err != nil {
+			fail(exitError, err)
+		}
+		// 最新的交易在前
+		for i, j := 0, len(txs)-1; i < j; i, j = i+1, j-1 {
+			txs[i], txs[j] = txs[j], txs[i]
+		}
+		total := len(txs)
+		if indexLimit > 0 && len(txs) > indexLimit {
+			txs = txs[:indexLimit]
+		}
+
+		printIndexedTxs(txs)
+		printResult(&indexListOutput{Command: command, Total: total, Txs: txs})
+	},
+}
+
+// indexOutput is the result schema of index command.
+type indexOutput struct {
+	Command string `json:"command"`
+	*elect.SyncResult
+}
+
+// indexListOutput is the result schema of index list command.
+type indexListOutput struct {
+	Command string             `json:"command"`
+	Total   int                `json:"total"` // number of the matched transactions before limit
+	Txs     []*elect.IndexedTx `json:"txs"`
+}
+
+func init() {
+	indexCmd.PersistentFlags().StringVar(&indexDir, "db", "", "directory of the index database, overrides indexDir of config")
+	indexCmd.Flags().Uint64Var(&indexFrom, "from", 0, "the first block to index if nothing is indexed")
+	indexListCmd.Flags().Uint64Var(&indexFrom, "from", 0, "list the transactions from the block")
+	indexListCmd.Flags().StringVar(&indexAddr, "address", "", "list the transactions sent by the address")
+	indexListCmd.Flags().StringVar(&indexMethod, "method", "", "list the transactions of the contract methods, such as voteWitnesses, separated by comma")
+	indexListCmd.Flags().IntVar(&indexLimit, "limit", 50, "maximum number of the transactions, 0 means no limit")
+	indexCmd.AddCommand(indexListCmd)
+}
+
+// openIndex opens the index database of the chain of node.
+func openIndex(e *elect.Election) *elect.Index {
+	ix, err := e.OpenIndex(indexDir)
+	if err != nil {
+		fail(exitError, err)
+	}
+	return ix
+}
+
+// printIndexedTxs prints the indexed transactions in text format.
+func printIndexedTxs(txs []*elect.IndexedTx) {
+	if output != outputText {
+		return
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "BLOCK\tTIME\tFROM\tMETHOD\tRESULT\tHASH")
+	for _, tx := range txs {
+		method, result := tx.Method, "ok"
+		if method == "" {
+			method = "unknown"
+		}
+		if !tx.Success {
+			result = "failed"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\t%s\n", tx.BlockNumber, tx.Time.Format(time.RFC3339), tx.From.String(), method, result, tx.Hash.String())
+	}
+	tw.Flush()
+}
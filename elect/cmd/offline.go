@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+)
+
+var (
+	signedOut string
+	signYes   bool
+)
+
+var signCmd = &cobra.Command{
+	Use:   "sign unsignedTxFile",
+	Short: "Sign an unsigned transaction offline",
+	Long: `Sign displays and signs the unsigned transaction created by a command with
+--unsigned-out flag. It only needs the keystore, and doesn't connect to any node.`,
+	Example: `elect sign tx.json --out signed.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			usage(cmd)
+		}
+
+		if !signYes && output != outputText {
+			fail(exitUsage, fmt.Errorf("--yes is required with --output %s", output))
+		}
+
+		e := newOfflineElection()
+
+		utx := &elect.UnsignedTx{}
+		if err := readJSON(args[0], utx); err != nil {
+			fail(exitCode(err), err)
+		}
+		if err := utx.Verify(); err != nil {
+			fail(exitCode(err), err)
+		}
+
+		info("chain id:  %d\n", utx.ChainID)
+		info("from:      %s\n", utx.From.String())
+		info("to:        %s\n", utx.To.String())
+		info("nonce:     %d\n", utx.Nonce)
+		info("value:     %s\n", elect.NewAmount(utx.Value))
+		info("gas limit: %d\n", utx.GasLimit)
+		info("gas price: %s wei\n", utx.GasPrice)
+		info("method:    %s\n", utx.Method)
+		for i, arg := range utx.Args {
+			info("arg[%d]:    %s\n", i, arg)
+		}
+		if !signYes && !confirm("sign this transaction?") {
+			return
+		}
+
+		stx, err := e.SignTx(utx)
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+		if err := writeJSON(signedOut, stx); err != nil {
+			fail(exitCode(err), err)
+		}
+		info("signed transaction %s is written to: %s\n", stx.Hash.String(), signedOut)
+		printResult(&txOutput{
+			Command: command,
+			Status:  txSigned,
+			Hash:    stx.Hash.String(),
+			Method:  utx.Method,
+			Args:    utx.Args,
+			File:    signedOut,
+		})
+	},
+}
+
+var broadcastCmd = &cobra.Command{
+	Use:   "broadcast signedTxFile",
+	Short: "Broadcast a signed transaction",
+	Long: `Broadcast sends the transaction signed by sign command to the node,
+it doesn't need the keystore.`,
+	Example: `elect broadcast signed.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			usage(cmd)
+		}
+
+		e := newElection()
+
+		stx := &elect.SignedTx{}
+		if err := readJSON(args[0], stx); err != nil {
+			fail(exitCode(err), err)
+		}
+		txhash, err := e.SendSignedTxContext(cmdCtx, stx)
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+		sentTx(e, stx.Method, txhash, &txOutput{Command: command})
+	},
+}
+
+func init() {
+	signCmd.Flags().StringVarP(&signedOut, "out", "o", "signed.json", "the file to write the signed transaction")
+	signCmd.Flags().BoolVarP(&signYes, "yes", "y", false, "sign without confirmation")
+}
+
+// confirm asks user a yes/no question, returns true if the answer is yes.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
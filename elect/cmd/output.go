@@ -0,0 +1,362 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+)
+
+// Output formats of the --output flag.
+const (
+	outputText  = "text"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+	outputTable = "table"
+)
+
+// Exit codes of elect, the code name is reported in the error output.
+const (
+	exitError    = 1 // general error
+	exitUsage    = 2 // invalid command, flag or argument
+	exitConfig   = 3 // invalid or missing config
+	exitTxFailed = 4 // transaction is failed or would fail
+	exitTimeout  = 5 // transaction is not mined before timeout
+	exitCheck    = 6 // checks before creating transaction failed, such as no stake
+	exitRPC      = 7 // requesting the node failed
+)
+
+var exitCodeNames = map[int]string{
+	exitError:    "error",
+	exitUsage:    "usage",
+	exitConfig:   "config",
+	exitTxFailed: "tx_failed",
+	exitTimeout:  "timeout",
+	exitCheck:    "check",
+	exitRPC:      "rpc",
+}
+
+// checkErrors are the errors of checks before creating transaction.
+var checkErrors = []error{
+	elect.ErrNoStake,
+	elect.ErrNotVoted,
+	elect.ErrNotCandidate,
+	elect.ErrAlreadyProxy,
+	elect.ErrNotProxy,
+	elect.ErrHasProxy,
+	elect.ErrNoProxy,
+	elect.ErrProxySelf,
+	elect.ErrTooManyCandidates,
+	elect.ErrInsufficientBalance,
+	elect.ErrStakeTooSmall,
+	elect.ErrStakeNotWhole,
+	elect.ErrBountyNotEnough,
+	elect.ErrCooldown,
+	elect.ErrPayoutPaid,
+	vntelection.ErrCandiNameLenInvalid,
+	vntelection.ErrCandiUrlLenInvalid,
+	vntelection.ErrCandiNameInvalid,
+	vntelection.ErrCandiInfoDup,
+	vntelection.ErrCandiAlreadyRegistered,
+}
+
+// exitCode returns the exit code of the error returned by Election.
+func exitCode(err error) int {
+	if cmdCtx.Err() == context.DeadlineExceeded {
+		return exitTimeout
+	}
+	var rpcErr *elect.ErrRPC
+	if errors.As(err, &rpcErr) {
+		return exitRPC
+	}
+	for _, e := range checkErrors {
+		if errors.Is(err, e) {
+			return exitCheck
+		}
+	}
+	return exitError
+}
+
+var (
+	output  string
+	command string // the running command path without "elect", such as "query"
+)
+
+// errorOutput is the schema of error written to stderr in json, yaml and table format.
+type errorOutput struct {
+	Command string      `json:"command"`
+	Error   errorDetail `json:"error"`
+}
+
+type errorDetail struct {
+	Code     string `json:"code"`
+	ExitCode int    `json:"exitCode"`
+	Message  string `json:"message"`
+}
+
+// setOutput records the running command and checks the --output flag.
+func setOutput(cmd *cobra.Command) {
+	command = strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+	switch output {
+	case outputText, outputJSON, outputYAML, outputTable:
+	default:
+		bad := output
+		output = outputText
+		fail(exitUsage, fmt.Errorf("invalid output format: %s, should be one of text, json, yaml and table", bad))
+	}
+}
+
+// info prints the human readable message in text format only.
+func info(format string, a ...interface{}) {
+	if output == outputText {
+		fmt.Printf(format, a...)
+	}
+}
+
+// printResult prints the result to stdout in json, yaml or table format, the text
+// format is printed by info.
+func printResult(v interface{}) {
+	if output == outputText {
+		return
+	}
+	if err := encode(os.Stdout, v); err != nil {
+		fail(exitError, err)
+	}
+}
+
+// fail prints the error to stderr and exits with code.
+func fail(code int, err error) {
+	if output == outputText {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+	} else {
+		encode(os.Stderr, &errorOutput{
+			Command: command,
+			Error: errorDetail{
+				Code:     exitCodeNames[code],
+				ExitCode: code,
+				Message:  err.Error(),
+			},
+		})
+	}
+	os.Exit(code)
+}
+
+// usage prints the help of command to stderr and exits with usage code.
+func usage(cmd *cobra.Command) {
+	cmd.SetOutput(os.Stderr)
+	cmd.Help()
+	os.Exit(exitUsage)
+}
+
+// encode writes v to w in the json, yaml or table format.
+func encode(w io.Writer, v interface{}) error {
+	if output == outputJSON {
+		data, err := json.MarshalIndent(v, "", "    ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	generic, err := toGeneric(v)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if output == outputYAML {
+		writeYAML(&buf, generic, 0)
+	} else {
+		writeTable(&buf, generic)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+// toGeneric converts v to the generic value decoded from its json encoding, so that the
+// yaml and table output have the same fields as json output.
+func toGeneric(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var generic interface{}
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// writeYAML writes the generic value in yaml block style, strings are double quoted
+// the same as json, which is valid yaml.
+func writeYAML(buf *bytes.Buffer, v interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(v) {
+			if isBlock(v[k]) {
+				fmt.Fprintf(buf, "%s%s:\n", pad, k)
+				writeYAML(buf, v[k], indent+2)
+			} else {
+				fmt.Fprintf(buf, "%s%s: %s\n", pad, k, scalar(v[k]))
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if !isBlock(item) {
+				fmt.Fprintf(buf, "%s- %s\n", pad, scalar(item))
+				continue
+			}
+			// write the item at the next level, then put the dash before its first line
+			var child bytes.Buffer
+			writeYAML(&child, item, indent+2)
+			fmt.Fprintf(buf, "%s- %s", pad, child.Bytes()[indent+2:])
+		}
+	default:
+		fmt.Fprintf(buf, "%s%s\n", pad, scalar(v))
+	}
+}
+
+// writeTable writes the scalar fields of the generic value as a key value table, nested
+// objects are flattened with dotted keys, and lists of objects are written as separate
+// tables following it.
+func writeTable(buf *bytes.Buffer, v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		m = map[string]interface{}{"result": v}
+	}
+
+	var (
+		rows      [][]string
+		listNames []string
+		lists     = make(map[string][]interface{})
+	)
+	var flatten func(prefix string, m map[string]interface{})
+	flatten = func(prefix string, m map[string]interface{}) {
+		for _, k := range sortedKeys(m) {
+			key := prefix + k
+			switch value := m[k].(type) {
+			case map[string]interface{}:
+				flatten(key+".", value)
+			case []interface{}:
+				if isObjectList(value) {
+					listNames = append(listNames, key)
+					lists[key] = value
+				} else {
+					rows = append(rows, []string{key, cell(value)})
+				}
+			default:
+				rows = append(rows, []string{key, cell(value)})
+			}
+		}
+	}
+	flatten("", m)
+
+	tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "KEY\tVALUE")
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	tw.Flush()
+
+	for _, name := range listNames {
+		var columns []string
+		seen := make(map[string]bool)
+		for _, item := range lists[name] {
+			for _, k := range sortedKeys(item.(map[string]interface{})) {
+				if !seen[k] {
+					seen[k] = true
+					columns = append(columns, k)
+				}
+			}
+		}
+
+		fmt.Fprintf(buf, "\n%s:\n", name)
+		tw := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, item := range lists[name] {
+			obj := item.(map[string]interface{})
+			cells := make([]string, len(columns))
+			for i, k := range columns {
+				cells[i] = cell(obj[k])
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+		tw.Flush()
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isBlock returns true if v is a non-empty object or list.
+func isBlock(v interface{}) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	}
+	return false
+}
+
+func isObjectList(list []interface{}) bool {
+	if len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if _, ok := item.(map[string]interface{}); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// scalar returns the yaml representation of the scalar or empty value.
+func scalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		data, _ := json.Marshal(v)
+		return string(data)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return fmt.Sprint(v)
+}
+
+// cell returns the table cell of the value, strings are not quoted and nested values
+// are written in compact json.
+func cell(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
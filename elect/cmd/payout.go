@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+)
+
+var (
+	payoutPeriod    string
+	payoutCandidate string
+	payoutBounty    string
+	payoutPercent   float64
+	payoutMin       string
+	payoutExclude   string
+	payoutNoSync    bool
+)
+
+var payoutCmd = &cobra.Command{
+	Use:   "payout",
+	Short: "Share the bounty of a witness with its voters",
+	Long: `Payout shares a percent of the bounty of a candidate with its voters in
+proportion to their current votes, and sends a value transfer to each voter
+from the account of config. The voters are found from the local index, see
+the voters command. The votes delegated to a proxy are shared with the
+delegators of the proxy.
+
+The bounty is the bounty extracted since the last paid period by default,
+--bounty extractable uses the rest of bounty can be extracted, or an amount
+of VNT. Each period is recorded in the payout ledger when the transfers are
+signed, and is paid only once. If sending is interrupted, run payout of the
+same period again to send the rest of the recorded transfers, the transfers
+whose nonces are used by other transactions are signed again.
+
+Use --dry-run to show the payout report without signing and sending. Syncing
+the index and sending are only limited by an explicitly set --timeout.`,
+	Example: `elect payout --period 2019-06 --percent 50 --dry-run
+elect payout --period 2019-06 --percent 50 --min 0.1 --exclude 0x122369f04f32269598789998de33e3d56e2c507a
+elect payout --period 2019-07 --percent 60 --bounty 3000`,
+	Annotations: map[string]string{annotationNoDeadline: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+		if payoutPeriod == "" {
+			fail(exitUsage, fmt.Errorf("--period is required"))
+		}
+
+		var e *elect.Election
+		if dryRun {
+			e = newWatchElection()
+		} else {
+			e = newElection()
+		}
+		req := &elect.PayoutRequest{
+			Period:    payoutPeriod,
+			Candidate: addressOrSender(e, payoutCandidate),
+			Percent:   payoutPercent,
+		}
+		switch payoutBounty {
+		case elect.BountyExtracted, elect.BountyExtractable:
+			req.Source = payoutBounty
+		default:
+			bounty, err := elect.ParseAmount(payoutBounty)
+			if err != nil {
+				fail(exitUsage, fmt.Errorf("invalid bounty: %s", err))
+			}
+			req.Bounty = bounty
+		}
+		if payoutMin != "" {
+			min, err := elect.ParseAmount(payoutMin)
+			if err != nil {
+				fail(exitUsage, fmt.Errorf("invalid minimum amount: %s", err))
+			}
+			req.MinAmount = min
+		}
+		for _, a := range strings.Split(payoutExclude, ",") {
+			if a = strings.TrimSpace(a); a == "" {
+				continue
+			}
+			if !common.IsHexAddress(a) {
+				fail(exitUsage, fmt.Errorf("invalid address to exclude: %s", a))
+			}
+			req.Exclude = append(req.Exclude, common.HexToAddress(a))
+		}
+
+		ix := openIndex(e)
+		defer ix.Close()
+		if !payoutNoSync {
+			if _, err := ix.Sync(cmdCtx, 0, nil); err != nil {
+				fail(exitCode(err), fmt.Errorf("sync index error: %s", err))
+			}
+		}
+		p, err := ix.PlanPayout(cmdCtx, req)
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+
+		out := &payoutOutput{Command: command, Status: payoutPlanned, Payout: p}
+		if !dryRun {
+			if err := e.Pay(cmdCtx, p); err != nil {
+				fail(exitCode(err), fmt.Errorf("%s, run payout of the period again to continue", err))
+			}
+			// 重新读取账本，继续发送时以账本中的记录为准
+			payouts, err := e.Payouts(p.Candidate)
+			if err != nil {
+				fail(exitError, err)
+			}
+			for _, recorded := range payouts {
+				if recorded.Period == p.Period {
+					out.Payout = recorded
+				}
+			}
+			out.Status = payoutPaid
+		}
+		printPayout(out)
+		printResult(out)
+	},
+}
+
+// Status of payout in payoutOutput.
+const (
+	payoutPlanned = "planned" // the payout is only reported by --dry-run
+	payoutPaid    = "paid"    // the transfers are sent
+)
+
+// payoutOutput is the result schema of payout command.
+type payoutOutput struct {
+	Command string `json:"command"`
+	Status  string `json:"status"`
+	*elect.Payout
+}
+
+func init() {
+	payoutCmd.Flags().StringVar(&payoutPeriod, "period", "", "name of the payout period, such as 2019-06, each period is paid only once")
+	payoutCmd.Flags().StringVar(&payoutCandidate, "candidate", "", "address of the candidate, default is the sender of config")
+	payoutCmd.Flags().StringVar(&payoutBounty, "bounty", elect.BountyExtracted, "bounty to share: extracted, extractable, or an amount of VNT")
+	payoutCmd.Flags().Float64Var(&payoutPercent, "percent", 0, "percent of the bounty shared with voters")
+	payoutCmd.Flags().StringVar(&payoutMin, "min", "", "skip the voters whose share is less than the amount")
+	payoutCmd.Flags().StringVar(&payoutExclude, "exclude", "", "addresses excluded from the payout, separated by comma")
+	payoutCmd.Flags().StringVar(&indexDir, "db", "", "directory of the index database, overrides indexDir of config")
+	payoutCmd.Flags().BoolVar(&payoutNoSync, "no-sync", false, "use the index as it is without syncing it to the latest block")
+}
+
+// printPayout prints the payout report in text format.
+func printPayout(out *payoutOutput) {
+	if output != outputText {
+		return
+	}
+	p := out.Payout
+	fmt.Printf("period:       %s\n", p.Period)
+	fmt.Printf("candidate:    %s\n", p.Candidate.String())
+	if p.Source != "" {
+		fmt.Printf("bounty:       %s (%s)\n", p.Bounty, p.Source)
+	} else {
+		fmt.Printf("bounty:       %s\n", p.Bounty)
+	}
+	fmt.Printf("shared:       %s (%v%%)\n", p.Pool, p.Percent)
+	fmt.Printf("paid:         %s, dust %s\n", p.Paid, p.Dust)
+	fmt.Printf("gas cost:     %s\n\n", p.GasCost)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tPROXY\tVOTES\tSHARE\tAMOUNT\tSTATUS\tHASH")
+	for _, payee := range p.Payees {
+		proxy, hash := "-", "-"
+		if payee.Proxy != nil {
+			proxy = payee.Proxy.String()
+		}
+		if payee.Hash != nil {
+			hash = payee.Hash.String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.4f%%\t%s\t%s\t%s\n", payee.Address.String(), proxy, payee.Votes, payee.Share, payee.Amount.VNT(), payee.Status, hash)
+	}
+	tw.Flush()
+	if out.Status == payoutPlanned {
+		fmt.Println("\ndry run, the transfers are not signed or sent")
+	}
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+)
+
+var speedupCmd = &cobra.Command{
+	Use:   "speedup txHash",
+	Short: "Speed up a pending transaction",
+	Long: `Speed up resends the pending transaction with the same nonce and payload at
+a higher gas price, and waits until one of the transactions is mined.`,
+	Example: `elect speedup 0x123...456 --gas-price 30000000000`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			usage(cmd)
+		}
+
+		e := newElection()
+		replaceTx(e, "speedup", common.HexToHash(args[0]), e.SpeedUpContext)
+	},
+}
+
+var cancelCmd = &cobra.Command{
+	Use:   "cancel txHash",
+	Short: "Cancel a pending transaction",
+	Long: `Cancel replaces the pending transaction with a zero value transfer to
+yourself at a higher gas price, and waits until one of the transactions is mined.`,
+	Example: `elect cancel 0x123...456`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			usage(cmd)
+		}
+
+		e := newElection()
+		replaceTx(e, "cancel", common.HexToHash(args[0]), e.CancelContext)
+	},
+}
+
+// replaceTx replaces the pending transaction and tracks which transaction is mined.
+// The replacement is always signed and sent, --dry-run and --unsigned-out are rejected.
+func replaceTx(e *elect.Election, name string, txhash common.Hash, replace func(context.Context, common.Hash) (common.Hash, error)) {
+	if dryRun || unsignedOut != "" {
+		fail(exitUsage, fmt.Errorf("--dry-run and --unsigned-out are not supported by %s", name))
+	}
+
+	newHash, err := replace(cmdCtx, txhash)
+	if err != nil {
+		fail(exitCode(err), err)
+	}
+	out := &txOutput{
+		Command:  command,
+		Status:   txSent,
+		Hash:     newHash.String(),
+		Replaced: txhash.String(),
+	}
+	if utx := e.LastSentTx(); utx != nil {
+		out.Method, out.Args = utx.Method, utx.Args
+	}
+	info("%s transaction send success, transaction hash: %s\n", name, newHash.String())
+
+	ret, err := e.WaitAnyMined(cmdCtx, txhash, newHash)
+	if err != nil {
+		printResult(out)
+		fail(exitCode(err), err)
+	}
+
+	minedTx(ret, out)
+	out.MinedHash = ret.Hash.String()
+	info("transaction %s mined, block number: %s, gas used: %d, status: %s\n", ret.Hash.String(), ret.BlockNumber, ret.GasUsed, out.Status)
+	if ret.Hash != newHash {
+		out.Status = txDropped
+		printResult(out)
+		fail(exitTxFailed, fmt.Errorf("the original transaction is mined, %s failed", name))
+	}
+	printResult(out)
+	if out.Status == txFailed {
+		fail(exitTxFailed, fmt.Errorf("transaction %s is failed", newHash.String()))
+	}
+}
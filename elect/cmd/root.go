@@ -1,8 +1,9 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -13,20 +14,56 @@ var rootCmd = &cobra.Command{
 	Long: `This tool is used to take part in VNT hubble network election.
 You can do any operation of election contract, more information
 see help command.`,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		setOutput(cmd)
+		if _, ok := cmd.Annotations[annotationNoDeadline]; ok && !cmd.Flag("timeout").Changed {
+			cmdCtx, cmdCancel = context.WithCancel(context.Background())
+		} else {
+			cmdCtx, cmdCancel = context.WithTimeout(context.Background(), cmdTimeout)
+		}
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Using `elect -h` to see how to use elect.")
 	},
 }
 
+// annotationNoDeadline marks the long-running commands, which are not limited by the
+// default --timeout, only by an explicitly set one.
+const annotationNoDeadline = "noDeadline"
+
+var (
+	cmdTimeout time.Duration
+	cmdCtx     = context.Background() // context of the command, expired after --timeout
+	cmdCancel  context.CancelFunc
+)
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	rootCmd.SilenceErrors = true
+	err := rootCmd.Execute()
+	if cmdCancel != nil {
+		cmdCancel()
+	}
+	if err != nil {
+		fail(exitUsage, err)
 	}
 }
 
 func init() {
 	// Set flags of elect command
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "path of config file, default is searched in ./config.json, $XDG_CONFIG_HOME/elect/ and ~/.elect/ [$ELECT_CONFIG]")
+	rootCmd.PersistentFlags().StringVar(&rpcUrl, "rpc", "", "RPC URL of the node, overrides rpcUrl of config [$ELECT_RPC]")
+	rootCmd.PersistentFlags().StringVar(&chainID, "chain-id", "", "chain id of the network, overrides chainID of config [$ELECT_CHAIN_ID]")
+	rootCmd.PersistentFlags().StringVar(&sender, "sender", "", "address of the account, overrides sender of config [$ELECT_SENDER]")
+	rootCmd.PersistentFlags().StringVar(&keystoreDir, "keystore", "", "keystore directory, overrides keystoreDir of config [$ELECT_KEYSTORE]")
+	rootCmd.PersistentFlags().StringVar(&account, "account", "", "name or address of the configured account to use, default is the sender of config")
+	rootCmd.PersistentFlags().StringVar(&output, "output", outputText, "output format of the result: text, json, yaml or table")
+	rootCmd.PersistentFlags().BoolVar(&waitMined, "wait", false, "wait for the transaction to be mined and report the receipt")
+	rootCmd.PersistentFlags().DurationVar(&cmdTimeout, "timeout", 5*time.Minute, "the maximum duration of the command, including waiting for the transaction to be mined, long-running commands have no limit unless it's set")
+	rootCmd.PersistentFlags().DurationVar(&rpcTimeout, "rpc-timeout", 0, "timeout of each RPC call, overrides rpcTimeout of config, default 30s")
+	rootCmd.PersistentFlags().StringVar(&gasPrice, "gas-price", "", "gas price of the transaction in wei, overrides the gas strategy of config")
+	rootCmd.PersistentFlags().Uint64Var(&gasLimit, "gas-limit", 0, "gas limit of the transaction, overrides the gas strategy of config")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "simulate the transaction against the pending state without signing and sending it")
+	rootCmd.PersistentFlags().StringVar(&unsignedOut, "unsigned-out", "", "write the unsigned transaction to the file instead of signing and sending it")
 	// Add sub commands
 	rootCmd.AddCommand(
 		stakeCmd,
@@ -39,5 +76,19 @@ func init() {
 		stopProxyCmd,
 		setProxyCmd,
 		cancelProxyCmd,
-		queryCmd)
+		extractBountyCmd,
+		signCmd,
+		broadcastCmd,
+		speedupCmd,
+		cancelCmd,
+		queryCmd,
+		candidatesCmd,
+		statusCmd,
+		weightCmd,
+		indexCmd,
+		votersCmd,
+		payoutCmd,
+		watchCmd,
+		accountsCmd,
+		configCmd)
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+)
+
+var statusAddr string
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the election status of account",
+	Long: `Status shows the election position of account in one place: balance, stake,
+vote weight, voted candidates with their rank, proxy and the next allowed time
+of unstaking and voting. If the account is a witness candidate, its rank, vote
+count and extractable bounty are shown too. The times are based on the time of
+the latest block.`,
+	Example: `elect status
+elect status --address 0x122369f04f32269598789998de33e3d56e2c507a --rpc http://localhost:8880`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+
+		e := newWatchElection()
+		s, err := e.StatusOfContext(cmdCtx, addressOrSender(e, statusAddr))
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+		printStatus(s)
+		printResult(&statusOutput{Command: command, Status: s})
+	},
+}
+
+// statusOutput is the result schema of status command.
+type statusOutput struct {
+	Command string `json:"command"`
+	*elect.Status
+}
+
+func init() {
+	statusCmd.Flags().StringVar(&statusAddr, "address", "", "address of the account, default is the sender of config")
+}
+
+// printStatus prints the status in text format.
+func printStatus(s *elect.Status) {
+	info("account:        %s\n", s.Address.String())
+	info("chain time:     %s\n", s.ChainTime.Format(time.RFC3339))
+	info("balance:        %s\n", s.Balance)
+	info("stake:          %s\n", s.Stake)
+	if s.UnstakeAllowed != nil {
+		info("unstake:        %s\n", allowedAt(*s.UnstakeAllowed, s.ChainTime))
+	}
+	info("vote weight:    %s (last vote %s)\n", s.VoteWeight, s.LastVoteCount)
+	if s.NextVoteAllowed != nil {
+		info("next vote:      %s\n", allowedAt(*s.NextVoteAllowed, s.ChainTime))
+	}
+	if s.Proxy != nil {
+		info("proxy:          %s\n", s.Proxy.String())
+	}
+	if s.IsProxy {
+		info("proxy votes:    %s\n", s.ProxyVoteCount)
+	}
+	for _, c := range s.VotedCandidates {
+		info("voted:          %s %s rank %d active %t votes %s\n", c.Address.String(), c.Name, c.Rank, c.Active, c.VoteCount)
+	}
+	if c := s.Candidate; c != nil {
+		info("candidate:      %s rank %d active %t votes %s\n", c.Name, c.Rank, c.Active, c.VoteCount)
+		info("bounty:         %s\n", c.ExtractableBounty)
+		if c.NextExtractAllowed != nil {
+			info("next extract:   %s\n", allowedAt(*c.NextExtractAllowed, s.ChainTime))
+		}
+	}
+}
+
+// allowedAt returns the description of the time allowed, relative to the chain time.
+func allowedAt(t, now time.Time) string {
+	if !t.After(now) {
+		return fmt.Sprintf("allowed now (since %s)", t.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("allowed at %s (in %s)", t.Format(time.RFC3339), t.Sub(now).Round(time.Second))
+}
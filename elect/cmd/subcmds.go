@@ -1,34 +1,30 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"math/big"
+	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
 )
 
 var stakeCmd = &cobra.Command{
-	Use:   "stake vntCount",
+	Use:   "stake amount",
 	Short: "Stake vnt token",
 	Long: `Stake provides checks before creating transaction to stake, 
-and sends the transaction if it may execute success.`,
-	Example: "elect stake 1",
+and sends the transaction if it may execute success. The amount is whole VNT
+by default, such as 12 or 12vnt, or in wei, such as 1000000000000000000wei,
+or all to stake the whole VNT of the balance except the gas reserve.`,
+	Example: "elect stake 12\nelect stake all",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.Stake(args[0]); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("stake transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "stake", func() (common.Hash, error) { return e.StakeContext(cmdCtx, args[0]) })
 	},
 }
 
@@ -40,19 +36,11 @@ and sends the transaction if it may execute success.`,
 	Example: "elect unstake",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.Unstake(); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("unstake transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "unstake", withContext(e.UnstakeContext))
 	},
 }
 
@@ -64,19 +52,11 @@ witness, and sends the transaction if it may execute success.`,
 	Example: "elect register nodeName nodeUrl website",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 3 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.RegisterWitness(args[0], args[1], args[2]); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("register witness transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "register witness", func() (common.Hash, error) { return e.RegisterWitnessContext(cmdCtx, args[0], args[1], args[2]) })
 	},
 }
 
@@ -88,19 +68,11 @@ witness, and sends the transaction if it may execute success.`,
 	Example: "elect unregister",
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.UnregisterWitness(); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("unregister witness transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "unregister witness", withContext(e.UnregisterWitnessContext))
 	},
 }
 
@@ -112,19 +84,11 @@ and sends the transaction if it may execute success.`,
 	Example: `elect vote "0x123....456" "0x789...123"`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) <= 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.Vote(args); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("vote witness transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "vote witness", func() (common.Hash, error) { return e.VoteContext(cmdCtx, args) })
 	},
 }
 
@@ -136,19 +100,11 @@ the vote, and sends the transaction if it may execute success.`,
 	Example: `elect cancelVote`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.CancelVote(); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("cancel vote witness transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "cancel vote witness", withContext(e.CancelVoteContext))
 	},
 }
 
@@ -160,19 +116,11 @@ a proxy, and sends the transaction if it may execute success.`,
 	Example: `elect startProxy`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.StartProxy(); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("start proxy transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "start proxy", withContext(e.StartProxyContext))
 	},
 }
 
@@ -184,19 +132,11 @@ normal voter, and sends the transaction if it may execute success.`,
 	Example: `elect stopProxy`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.StopProxy(); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("stop proxy transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "stop proxy", withContext(e.StopProxyContext))
 	},
 }
 
@@ -208,19 +148,11 @@ as your proxy, and sends the transaction if it may execute success.`,
 	Example: `elect setProxy proxyAccountAddr`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
-		if txhash, err := e.SetProxy(args[0]); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("stop proxy transaction send success, transaction hash: %s\n", txhash.String())
-		}
+		e := newElection()
+		runTx(e, "set proxy", func() (common.Hash, error) { return e.SetProxyContext(cmdCtx, args[0]) })
 	},
 }
 
@@ -232,19 +164,32 @@ your proxy, and sends the transaction if it may execute success.`,
 	Example: `elect cancelProxy`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) > 0 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
-		e, err := elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
+		e := newElection()
+		runTx(e, "cancel proxy", withContext(e.CancelProxyContext))
+	},
+}
+
+var extractBountyCmd = &cobra.Command{
+	Use:   "extractBounty",
+	Short: "Extract bounty of witness candidate",
+	Long: `Extract bounty provides checks before creating a transaction to extract
+the bounty of witness, and sends the transaction if it may execute success.`,
+	Example: `elect extractBounty`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
 		}
-		if txhash, err := e.CancelProxy(); err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("cancel proxy transaction send success, transaction hash: %s\n", txhash.String())
+
+		e := newElection()
+		bounty, err := e.QueryExtractableBountyContext(cmdCtx)
+		if err != nil {
+			fail(exitCode(err), err)
 		}
+		info("extractable bounty: %s\n", bounty)
+		runTxOutput(e, "extract bounty", withContext(e.ExtractBountyContext), &txOutput{Bounty: bounty})
 	},
 }
 
@@ -252,12 +197,14 @@ var queryCmd = &cobra.Command{
 	Use:   "query",
 	Short: "Query election data",
 	Long: `Query supports getting the stake or vote information of account, 
-and getting witness candidates list and rest bounty.`,
-	Example: `elect query stake/vote/candidates/rest`,
+and getting witness candidates list and rest bounty. The stake or vote
+information of any account can be queried by --address. Query needs no
+keystore, and the config file is optional if --rpc is set.`,
+	Example: `elect query stake/vote/candidates/rest
+elect query vote --address 0x122369f04f32269598789998de33e3d56e2c507a --rpc http://localhost:8880`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
-			cmd.Help()
-			return
+			usage(cmd)
 		}
 
 		var (
@@ -266,37 +213,83 @@ and getting witness candidates list and rest bounty.`,
 			e   *elect.Election
 		)
 
-		e, err = elect.NewElection("./config.json")
-		if err != nil {
-			panic(err)
-		}
+		e = newWatchElection()
+		addr := queryAddress(e, args[0])
 
 		switch args[0] {
 		case "stake":
-			ret, err = e.QueryStake()
+			ret, err = e.QueryStakeOfContext(cmdCtx, addr)
 		case "vote":
-			ret, err = e.QueryVote()
+			ret, err = e.QueryVoteOfContext(cmdCtx, addr)
 		case "candidates":
-			ret, err = e.QueryCandidates()
+			ret, err = e.QueryCandidatesContext(cmdCtx)
 		case "rest":
-			var rest *big.Int
-			rest, err = e.QueryRestVNTBounty()
+			var rest *elect.Amount
+			rest, err = e.QueryRestVNTBountyContext(cmdCtx)
 			if err != nil {
 				break
 			}
 
-			ret = []byte(rest.String() + " wei")
+			ret, err = json.Marshal(rest)
+			if output == outputText {
+				ret = []byte(rest.String())
+			}
 		default:
-			fmt.Printf("error: query not support %s\n", args[0])
-			fmt.Printf("\nQuery help:\n")
-			cmd.Help()
-			return
+			fmt.Fprintf(os.Stderr, "error: query not support %s\n", args[0])
+			fmt.Fprintf(os.Stderr, "\nQuery help:\n")
+			usage(cmd)
 		}
 
 		if err != nil {
-			fmt.Printf("error: %s\n", err)
-		} else {
-			fmt.Printf("Result:\n%s\n", string(ret))
+			fail(exitCode(err), err)
+		}
+		info("Result:\n%s\n", string(ret))
+		out := &queryOutput{Command: command, Type: args[0], Result: json.RawMessage(ret)}
+		if addr != (common.Address{}) {
+			out.Address = addr.String()
 		}
+		printResult(out)
 	},
 }
+
+var queryAddr string
+
+func init() {
+	queryCmd.Flags().StringVar(&queryAddr, "address", "", "address of the account to query stake or vote, default is the sender of config")
+}
+
+// queryAddress returns the account to query stake or vote of, which is --address or the
+// sender of config, it's empty for the other queries.
+func queryAddress(e *elect.Election, typ string) common.Address {
+	if typ != "stake" && typ != "vote" {
+		if queryAddr != "" {
+			fail(exitUsage, fmt.Errorf("--address is only supported by query stake and vote"))
+		}
+		return common.Address{}
+	}
+	return addressOrSender(e, queryAddr)
+}
+
+// addressOrSender returns the address of the --address flag, or the sender of config if
+// the flag is not set.
+func addressOrSender(e *elect.Election, addr string) common.Address {
+	if addr != "" {
+		if !common.IsHexAddress(addr) {
+			fail(exitUsage, fmt.Errorf("invalid address: %s", addr))
+		}
+		return common.HexToAddress(addr)
+	}
+	if e.Sender() == (common.Address{}) {
+		fail(exitUsage, fmt.Errorf("no sender is configured, use --address to set the account to query"))
+	}
+	return e.Sender()
+}
+
+// queryOutput is the result schema of query command, result is the same as the json
+// returned by the query of Election, and the rest bounty is an amount.
+type queryOutput struct {
+	Command string          `json:"command"`
+	Type    string          `json:"type"`
+	Address string          `json:"address,omitempty"` // the account of query stake or vote
+	Result  json.RawMessage `json:"result"`
+}
@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+)
+
+var (
+	waitMined   bool
+	unsignedOut string
+	dryRun      bool
+)
+
+// Status of transaction in txOutput.
+const (
+	txUnsigned         = "unsigned"          // unsigned transaction is written to file
+	txSigned           = "signed"            // signed transaction is written to file
+	txSimulated        = "simulated"         // transaction would succeed
+	txSimulationFailed = "simulation_failed" // transaction would fail
+	txSent             = "sent"              // transaction is sent, but not waited
+	txSuccess          = "success"           // transaction is mined and succeeded
+	txFailed           = "failed"            // transaction is mined and failed
+	txDropped          = "dropped"           // the replaced transaction is mined instead
+)
+
+// txOutput is the result schema of commands creating transaction.
+type txOutput struct {
+	Command     string        `json:"command"`
+	Status      string        `json:"status"`
+	Hash        string        `json:"hash,omitempty"`
+	Method      string        `json:"method,omitempty"`
+	Args        []string      `json:"args,omitempty"`
+	BlockNumber *big.Int      `json:"blockNumber,omitempty"`
+	GasUsed     uint64        `json:"gasUsed,omitempty"`
+	Gas         uint64        `json:"gas,omitempty"`      // estimated gas of simulation
+	Error       string        `json:"error,omitempty"`    // error of simulation
+	File        string        `json:"file,omitempty"`     // file of the unsigned or signed transaction
+	Bounty      *elect.Amount `json:"bounty,omitempty"`   // extractable bounty
+	Replaced    string        `json:"replaced,omitempty"` // hash of the replaced transaction
+	MinedHash   string        `json:"minedHash,omitempty"`
+}
+
+// withContext returns the operation calling f with the context of command.
+func withContext(f func(context.Context) (common.Hash, error)) elect.Op {
+	return func() (common.Hash, error) { return f(cmdCtx) }
+}
+
+// runTx runs the election operation and prints the transaction hash. If --unsigned-out
+// is set, the unsigned transaction is written to the file instead of being sent. If
+// --dry-run is set, the transaction is only simulated.
+func runTx(e *elect.Election, name string, op elect.Op) {
+	runTxOutput(e, name, op, &txOutput{})
+}
+
+// runTxOutput is the same as runTx, but prints the result based on out.
+func runTxOutput(e *elect.Election, name string, op elect.Op, out *txOutput) {
+	out.Command = command
+	if dryRun {
+		simulateTx(e, name, op, out)
+		return
+	}
+
+	if unsignedOut != "" {
+		utx, err := e.BuildUnsignedTx(op)
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+		if err := writeJSON(unsignedOut, utx); err != nil {
+			fail(exitCode(err), err)
+		}
+		out.Status = txUnsigned
+		out.Method, out.Args = utx.Method, utx.Args
+		out.File = unsignedOut
+		info("unsigned %s transaction is written to: %s\n", name, unsignedOut)
+		printResult(out)
+		return
+	}
+
+	txhash, err := op()
+	if err != nil {
+		fail(exitCode(err), err)
+	}
+	sentTx(e, name, txhash, out)
+}
+
+// simulateTx simulates the election operation and prints the result, exits with
+// non-zero code if the transaction would fail.
+func simulateTx(e *elect.Election, name string, op elect.Op, out *txOutput) {
+	ret, err := e.SimulateContext(cmdCtx, op)
+	if err != nil {
+		fail(exitCode(err), err)
+	}
+
+	out.Method, out.Args = ret.Method, ret.Args
+	info("simulate %s transaction, method: %s, args: %v\n", name, ret.Method, ret.Args)
+	if !ret.Success {
+		out.Status = txSimulationFailed
+		out.Error = ret.Error
+		printResult(out)
+		fail(exitTxFailed, fmt.Errorf("transaction would fail, error: %s", ret.Error))
+	}
+	out.Status = txSimulated
+	out.Gas = ret.Gas
+	info("transaction would succeed, estimated gas: %d\n", ret.Gas)
+	printResult(out)
+}
+
+// sentTx prints the sent transaction, and waits it mined if --wait is set. It exits with
+// non-zero code if the transaction is failed.
+func sentTx(e *elect.Election, name string, txhash common.Hash, out *txOutput) {
+	out.Status = txSent
+	out.Hash = txhash.String()
+	if utx := e.LastSentTx(); utx != nil {
+		out.Method, out.Args = utx.Method, utx.Args
+	}
+	info("%s transaction send success, transaction hash: %s\n", name, txhash.String())
+
+	waitTx(e, txhash, out)
+	printResult(out)
+	if out.Status == txFailed {
+		fail(exitTxFailed, fmt.Errorf("transaction %s is failed", txhash.String()))
+	}
+}
+
+// waitTx waits the transaction mined and records the result if --wait is set.
+func waitTx(e *elect.Election, txhash common.Hash, out *txOutput) {
+	if !waitMined {
+		return
+	}
+
+	ret, err := e.WaitMined(cmdCtx, txhash)
+	if err != nil {
+		printResult(out)
+		fail(exitCode(err), err)
+	}
+	minedTx(ret, out)
+	info("transaction mined, block number: %s, gas used: %d, status: %s\n", ret.BlockNumber, ret.GasUsed, out.Status)
+}
+
+// minedTx records the result of the mined transaction.
+func minedTx(ret *elect.TxResult, out *txOutput) {
+	out.Status = txSuccess
+	if !ret.Success {
+		out.Status = txFailed
+	}
+	out.BlockNumber = ret.BlockNumber
+	out.GasUsed = ret.GasUsed
+}
+
+// writeJSON writes v to path, the file is only readable by the owner because it
+// contains the transactions of the account.
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, append(data, '\n'), 0600)
+}
+
+func readJSON(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("decode %s error: %s", path, err)
+	}
+	return nil
+}
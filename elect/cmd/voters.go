@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+	"github.com/vntchain/go-vnt/common"
+)
+
+var (
+	votersNoSync bool
+	votersCSV    string
+)
+
+var votersCmd = &cobra.Command{
+	Use:   "voters <candidate>",
+	Short: "List the voters of a candidate",
+	Long: `Voters lists the accounts voting for the candidate and their votes. The
+election contract only records the candidates voted by each voter, so the
+voters are found from the local index of the transactions of voting and
+proxy, and their votes are read at the latest block.
+
+The direct voters vote for the candidate by themselves, their votes include
+the votes delegated to them as proxies. The delegators set a proxy voting for
+the candidate, their votes are a part of the proxy votes of the proxy.
+
+The index is synced to the latest block first, see the index command, so
+it's only limited by an explicitly set --timeout.`,
+	Example: `elect voters 0x122369f04f32269598789998de33e3d56e2c507a
+elect voters 0x122369f04f32269598789998de33e3d56e2c507a --csv voters.csv`,
+	Annotations: map[string]string{annotationNoDeadline: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			usage(cmd)
+		}
+		if !common.IsHexAddress(args[0]) {
+			fail(exitUsage, fmt.Errorf("invalid candidate address: %s", args[0]))
+		}
+
+		ix := openIndex(newWatchElection())
+		defer ix.Close()
+		if !votersNoSync {
+			if _, err := ix.Sync(cmdCtx, 0, nil); err != nil {
+				fail(exitCode(err), fmt.Errorf("sync index error: %s", err))
+			}
+		}
+		cv, err := ix.Voters(cmdCtx, common.HexToAddress(args[0]))
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+
+		if votersCSV != "" {
+			if err := writeVotersCSV(votersCSV, cv.Voters); err != nil {
+				fail(exitError, err)
+			}
+			info("wrote %d voters to %s\n", len(cv.Voters), votersCSV)
+		}
+		printVoters(cv)
+		printResult(&votersOutput{Command: command, CandidateVoters: cv})
+	},
+}
+
+// votersOutput is the result schema of voters command.
+type votersOutput struct {
+	Command string `json:"command"`
+	*elect.CandidateVoters
+}
+
+func init() {
+	votersCmd.Flags().StringVar(&indexDir, "db", "", "directory of the index database, overrides indexDir of config")
+	votersCmd.Flags().BoolVar(&votersNoSync, "no-sync", false, "use the index as it is without syncing it to the latest block")
+	votersCmd.Flags().StringVar(&votersCSV, "csv", "", "export the voters to the CSV file, - for stdout")
+}
+
+// printVoters prints the voters in text format.
+func printVoters(cv *elect.CandidateVoters) {
+	if output != outputText || votersCSV == "-" {
+		return
+	}
+	name := cv.Name
+	if name == "" {
+		name = "not a candidate"
+	}
+	fmt.Printf("candidate:    %s (%s)\n", cv.Candidate.String(), name)
+	fmt.Printf("votes:        %s, found %s from %d voters indexed to block %d\n\n", cv.VoteCount, cv.TotalVotes, len(cv.Voters), cv.IndexedTo)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ADDRESS\tPROXY\tLAST VOTES\tPROXY VOTES\tVOTES\tLAST VOTE")
+	for _, v := range cv.Voters {
+		proxy, lastVote := "-", "-"
+		if v.Proxy != nil {
+			proxy = v.Proxy.String()
+		}
+		if v.LastVoteTime != nil {
+			lastVote = v.LastVoteTime.Format(time.RFC3339)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", v.Address.String(), proxy, v.LastVoteCount, v.ProxyVoteCount, v.Votes, lastVote)
+	}
+	tw.Flush()
+}
+
+// writeVotersCSV writes the voters to the CSV file, or stdout if path is -.
+func writeVotersCSV(path string, voters []*elect.Voter) error {
+	var w io.Writer = os.Stdout
+	if path != "-" {
+		f, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("create CSV file error: %s", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"address", "proxy", "lastVoteCount", "proxyVoteCount", "votes", "lastVoteTime"})
+	for _, v := range voters {
+		proxy, lastVote := "", ""
+		if v.Proxy != nil {
+			proxy = v.Proxy.String()
+		}
+		if v.LastVoteTime != nil {
+			lastVote = v.LastVoteTime.Format(time.RFC3339)
+		}
+		cw.Write([]string{v.Address.String(), proxy, v.LastVoteCount.String(), v.ProxyVoteCount.String(), v.Votes.String(), lastVote})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+)
+
+var (
+	watchAddr      string
+	watchCandidate string
+	watchMargin    string
+	watchTop       int
+	watchEvery     uint64
+	watchPoll      int
+	watchDisable   string
+	watchAlerts    []string
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch the election state and raise alerts",
+	Long: `Watch checks the candidate ranking, the stake, votes and bounty of the
+account at every new block, and raises alerts by the rules:
+
+  near_cutoff         the candidate is within --cutoff-margin votes of the cutoff
+  dropped_out         the candidate is not in the top --top candidates or inactive
+  candidate_inactive  a candidate voted by the account is inactive
+  bounty_extractable  the bounty of the account can be extracted
+  account_changed     the stake, votes or proxy of the account changed
+  node_error          querying the node failed
+
+An alert is raised when its condition becomes true, and again only after the
+condition becomes false and then true. The rules are set in the watch section
+of config, and the flags override them.
+
+It subscribes new heads if the node is connected by websocket, or polls the
+latest block every --poll seconds. The alerts are sent to the targets of
+--alert, which can be repeated:
+
+  stdout                     print the alert, in the format of --output
+  cmd:/path/to/hook arg      run the command with the alert in json from stdin
+  https://example.com/hook   post the alert in json to the webhook URL
+
+Watch runs until interrupted, --timeout is not applied unless it's set.`,
+	Example: `elect watch --rpc ws://localhost:8880 --cutoff-margin 100000
+elect watch --candidate 0x122369f04f32269598789998de33e3d56e2c507a --top 19 --alert stdout --alert https://example.com/hook
+elect watch --disable account_changed --alert "cmd:/usr/local/bin/notify.sh"`,
+	Annotations: map[string]string{annotationNoDeadline: ""},
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+		cfg := loadWatchConfig()
+		e, err := elect.NewWatchOnlyElection(cfg)
+		if err != nil {
+			fail(exitConfig, err)
+		}
+		applyFlags(e)
+
+		rules := cfg.Watch
+		if watchAddr != "" {
+			rules.Account = addressOrSender(e, watchAddr)
+		}
+		if watchCandidate != "" {
+			rules.Candidate = addressOrSender(e, watchCandidate)
+		}
+		if watchMargin != "" {
+			margin, ok := new(big.Int).SetString(watchMargin, 10)
+			if !ok || margin.Sign() < 0 {
+				fail(exitUsage, fmt.Errorf("invalid cutoff margin: %s", watchMargin))
+			}
+			rules.CutoffMargin = margin
+		}
+		if watchTop > 0 {
+			rules.TopN = watchTop
+		}
+		if watchEvery > 0 {
+			rules.Every = watchEvery
+		}
+		if watchPoll > 0 {
+			rules.PollInterval = watchPoll
+		}
+		if watchDisable != "" {
+			rules.Disable = strings.Split(watchDisable, ",")
+		}
+		if len(watchAlerts) > 0 {
+			rules.Alerts = watchAlerts
+		}
+		if len(rules.Alerts) == 0 {
+			rules.Alerts = []string{"stdout"}
+		}
+		sinks := make([]elect.AlertSink, len(rules.Alerts))
+		for i, target := range rules.Alerts {
+			if target == "stdout" && output != outputText {
+				sinks[i] = resultSink{}
+				continue
+			}
+			if sinks[i], err = elect.NewAlertSink(target); err != nil {
+				fail(exitUsage, err)
+			}
+		}
+
+		ctx, cancel := context.WithCancel(cmdCtx)
+		defer cancel()
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			cancel()
+		}()
+
+		info("watching the election, press Ctrl+C to stop\n")
+		err = e.Watch(ctx, &rules, func(a *elect.Alert) {
+			for i, s := range sinks {
+				if err := s.Send(ctx, a); err != nil {
+					fmt.Fprintf(os.Stderr, "send alert to %s error: %s\n", rules.Alerts[i], err)
+				}
+			}
+		})
+		if err != nil {
+			fail(exitCode(err), err)
+		}
+	},
+}
+
+// resultSink prints the alert in the format of --output.
+type resultSink struct{}
+
+func (resultSink) Send(ctx context.Context, a *elect.Alert) error {
+	printResult(&alertOutput{Command: command, Alert: a})
+	return nil
+}
+
+// alertOutput is the result schema of an alert of watch command.
+type alertOutput struct {
+	Command string `json:"command"`
+	*elect.Alert
+}
+
+func init() {
+	watchCmd.Flags().StringVar(&watchAddr, "address", "", "account whose stake, votes and bounty are watched, default is the sender of config")
+	watchCmd.Flags().StringVar(&watchCandidate, "candidate", "", "candidate whose rank is watched, default is the account if it's a candidate")
+	watchCmd.Flags().StringVar(&watchMargin, "cutoff-margin", "", "raise near_cutoff if the candidate is within the votes of the cutoff")
+	watchCmd.Flags().IntVar(&watchTop, "top", 0, "raise dropped_out if the candidate is not in the top N, default is witnessesNum of config")
+	watchCmd.Flags().Uint64Var(&watchEvery, "every", 0, "blocks between checks, default 1")
+	watchCmd.Flags().IntVar(&watchPoll, "poll", 0, "seconds of polling the latest block if the node doesn't support subscribing, default 5")
+	watchCmd.Flags().StringVar(&watchDisable, "disable", "", "rules disabled, separated by comma")
+	watchCmd.Flags().StringArrayVar(&watchAlerts, "alert", nil, "target of alerts: stdout, cmd:<command> or webhook URL, can be repeated, default stdout")
+}
@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vntchain/elect"
+)
+
+var (
+	weightAddr  string
+	weightStake string
+	weightWeeks int
+	weightStep  int
+)
+
+var weightCmd = &cobra.Command{
+	Use:   "weight",
+	Short: "Show the vote weight and its projection",
+	Long: `Weight shows the vote weight of account calculated the same as the election
+contract: stake * 2^(weeks since 2019 / 52). The votes are frozen at the last
+vote, so voting again increases the weight. It shows the frozen votes, the
+votes of voting now, and the votes of voting at the future dates.
+
+The stake is the stake count of the contract, --stake projects the weight of
+the stake count instead of querying the account.`,
+	Example: `elect weight
+elect weight --address 0x122369f04f32269598789998de33e3d56e2c507a --weeks 104 --step 8
+elect weight --stake 1000`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 0 {
+			usage(cmd)
+		}
+		if weightWeeks < 0 || weightStep <= 0 {
+			fail(exitUsage, fmt.Errorf("weeks should not be negative and step should be positive"))
+		}
+
+		e := newWatchElection()
+		var w *elect.VoteWeight
+		if weightStake != "" {
+			stake, ok := new(big.Int).SetString(weightStake, 10)
+			if !ok || stake.Sign() < 0 {
+				fail(exitUsage, fmt.Errorf("invalid stake count: %s", weightStake))
+			}
+			now, err := e.ChainTimeContext(cmdCtx)
+			if err != nil {
+				fail(exitCode(err), err)
+			}
+			w = &elect.VoteWeight{ChainTime: now, StakeCount: stake, LastVoteCount: big.NewInt(0)}
+			w.Revote = e.ProjectVoteWeight(stake, now)
+			w.Increase = w.Revote
+		} else {
+			var err error
+			if w, err = e.QueryVoteWeightOfContext(cmdCtx, addressOrSender(e, weightAddr)); err != nil {
+				fail(exitCode(err), err)
+			}
+		}
+
+		var projection []weightPoint
+		for week := 0; week <= weightWeeks; week += weightStep {
+			at := w.ChainTime.Add(time.Duration(week) * 7 * 24 * time.Hour)
+			projection = append(projection, weightPoint{Time: at, Weeks: week, Votes: e.ProjectVoteWeight(w.StakeCount, at)})
+		}
+
+		printWeight(w, projection)
+		printResult(&weightOutput{Command: command, VoteWeight: w, Projection: projection})
+	},
+}
+
+// weightOutput is the result schema of weight command.
+type weightOutput struct {
+	Command string `json:"command"`
+	*elect.VoteWeight
+	Projection []weightPoint `json:"projection"`
+}
+
+// weightPoint is the votes of voting at the time, weeks after the chain time.
+type weightPoint struct {
+	Time  time.Time `json:"time"`
+	Weeks int       `json:"weeks"`
+	Votes *big.Int  `json:"votes"`
+}
+
+func init() {
+	weightCmd.Flags().StringVar(&weightAddr, "address", "", "address of the account, default is the sender of config")
+	weightCmd.Flags().StringVar(&weightStake, "stake", "", "stake count to project instead of the stake of account")
+	weightCmd.Flags().IntVar(&weightWeeks, "weeks", 52, "weeks of the projection")
+	weightCmd.Flags().IntVar(&weightStep, "step", 4, "weeks between the dates of the projection")
+}
+
+// printWeight prints the vote weight and projection in text format.
+func printWeight(w *elect.VoteWeight, projection []weightPoint) {
+	if output != outputText {
+		return
+	}
+	if weightStake == "" {
+		fmt.Printf("account:      %s\n", w.Address.String())
+	}
+	fmt.Printf("chain time:   %s\n", w.ChainTime.Format(time.RFC3339))
+	fmt.Printf("stake count:  %s\n", w.StakeCount)
+	if w.LastVoteTime != nil {
+		fmt.Printf("last vote:    %s votes at %s\n", w.LastVoteCount, w.LastVoteTime.Format(time.RFC3339))
+	} else if weightStake == "" {
+		fmt.Printf("last vote:    never voted\n")
+	}
+	fmt.Printf("vote now:     %s votes (+%s)\n\n", w.Revote, w.Increase)
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATE\tWEEKS\tVOTES")
+	for _, p := range projection {
+		fmt.Fprintf(tw, "%s\t%d\t%s\n", p.Time.Format("2006-01-02"), p.Weeks, p.Votes)
+	}
+	tw.Flush()
+}
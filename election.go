@@ -2,10 +2,10 @@ package elect
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"math/big"
 	"os"
+	"strings"
 	"time"
 
 	"unicode"
@@ -15,7 +15,8 @@ import (
 	"github.com/vntchain/go-vnt/common"
 	"github.com/vntchain/go-vnt/core/types"
 	vntelection "github.com/vntchain/go-vnt/core/vm/election"
-	"github.com/vntchain/go-vnt/vntclient"
+	"github.com/vntchain/go-vnt/log"
+	"github.com/vntchain/go-vnt/rpc"
 )
 
 var (
@@ -27,142 +28,246 @@ var (
 // It checks conditions before creating transaction, signs the transaction
 // with your account and password.
 type Election struct {
-	cfgPath string // config.json的路径
-	cfg     *Config
-	wallet  accounts.Wallet  // 用于签名的钱包
-	account accounts.Account // config中配置的账号
-
-	vc  *vntclient.Client
-	ctx context.Context
+	cfgPath  string             // config.json的路径
+	cfg      *Config            // 当前账号生效的配置
+	fileCfg  *Config            // config.json中的配置
+	ks       *keystore.KeyStore // 钱包所在的keystore
+	wallet   accounts.Wallet    // 用于签名的钱包
+	account  accounts.Account   // config中配置的账号
+	password PasswordProvider   // 账号密码的来源，为nil时使用config中的配置
+
+	vc      *rpcClient
+	backend Backend          // 注入的节点客户端，为nil时连接config中的RpcUrl
+	signer  Signer           // 注入的签名者，为nil时使用keystore中的钱包
+	ctx     context.Context  // default context of the methods without ctx
+	now     func() time.Time // clock of the nonce cache
+	log     log.Logger
+
+	// handleTx handles the unsigned transaction instead of signing and sending, if it's not nil
+	handleTx func(*types.Transaction) (common.Hash, error)
+
+	// 只读模式，不加载钱包，只能查询
+	watchOnly bool
+
+	nonceLock *os.File           // lock of nonce cache, held from allocating nonce to sending transaction
+	lastTx    *types.Transaction // the last sent transaction
 }
 
 // NewElection returns a Election, or an error if initializing Election failed.
 func NewElection(configPath string) (*Election, error) {
-	e := &Election{
-		cfgPath: configPath,
-		ctx:     context.Background(),
-	}
+	e := newElection(configPath)
 	if err := e.init(); err != nil {
 		return nil, err
 	}
 	return e, nil
 }
 
+// NewElectionWithConfig returns a Election of the in-memory config, or an error if
+// initializing Election failed. The client, signer, clock and logger can be injected by
+// opts, such as WithClient. It doesn't connect to any node if neither RpcUrl of config
+// nor client is set, then it can only sign transactions.
+func NewElectionWithConfig(cfg *Config, opts ...Option) (*Election, error) {
+	e := newElection("", opts...)
+	e.setConfig(cfg)
+	if cfg.RpcUrl == "" && e.backend == nil {
+		return e, nil
+	}
+	if err := e.newClient(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewWatchOnlyElection returns a Election which can only query the node, it never loads
+// the keystore and signing transactions returns ErrWatchOnly. Sender of config is optional,
+// it's the account of QueryStake and QueryVote. The node is RpcUrl of config or the
+// client injected by WithClient, it returns an error if neither is set.
+func NewWatchOnlyElection(cfg *Config, opts ...Option) (*Election, error) {
+	e := newElection("", opts...)
+	e.setConfig(cfg)
+	e.watchOnly = true
+	if cfg.RpcUrl == "" && e.backend == nil {
+		return nil, fmt.Errorf("watch-only election requires rpcUrl of the node")
+	}
+	if err := e.newClient(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewOfflineElection returns a Election which doesn't connect to any node, it can only
+// sign transactions, or an error if loading config failed.
+func NewOfflineElection(configPath string) (*Election, error) {
+	e := newElection(configPath)
+	if err := e.loadCfg(e.cfgPath); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
 // init load config and set vntclient
 func (e *Election) init() error {
 	if err := e.loadCfg(e.cfgPath); err != nil {
 		return err
 	}
 
-	if err := e.newClient(); err != nil {
-		return err
+	return e.newClient()
+}
+
+// loadWallet loads the wallet of account when it's needed at the first time, so
+// the keystore is only required on the host which signs transactions.
+func (e *Election) loadWallet() error {
+	if e.wallet != nil {
+		return nil
 	}
 
-	e.account = accounts.Account{Address: e.cfg.Sender}
-	e.wallet = loadKSWallet(e.cfg.KeystoreDir, e.account)
+	e.ks, e.wallet = loadKSWallet(e.cfg.KeystoreDir, e.account)
 	if e.wallet == nil {
 		return fmt.Errorf("Not find keystore file of account: %s, in directory: %s\n", e.cfg.Sender.String(), e.cfg.KeystoreDir)
 	}
-
 	return nil
 }
 
 func (e *Election) loadCfg(cfgPath string) error {
-	f, err := os.Open(cfgPath)
+	cfg, err := LoadConfig(cfgPath)
 	if err != nil {
-		return fmt.Errorf("Open config file error: %s\n", err)
+		return err
 	}
-	defer f.Close()
+	e.setConfig(cfg)
+	return nil
+}
 
-	config := Config{}
-	e.cfg = &config
-	decoder := json.NewDecoder(f)
-	if err := decoder.Decode(&config); err != nil {
-		return fmt.Errorf("Decode config file error: %s\n", err)
+// setConfig sets the config and selects the default account of config.
+func (e *Election) setConfig(cfg *Config) {
+	e.cfg = cfg
+	e.fileCfg = cfg
+	if a := cfg.defaultAccount(); a != nil {
+		e.setAccount(a)
 	}
-
-	return nil
 }
 
+// newClient connects to RpcUrl of config, unless the client is injected.
 func (e *Election) newClient() error {
-	var err error
-	e.vc, err = vntclient.Dial(e.cfg.RpcUrl)
-	if err != nil {
-		return fmt.Errorf("Connect to ethereum RPC server failed. url: %s, err: %v\n", e.cfg.RpcUrl, err)
+	if e.backend == nil {
+		rc, err := rpc.Dial(e.cfg.RpcUrl)
+		if err != nil {
+			return fmt.Errorf("Connect to ethereum RPC server failed. url: %s, err: %w", e.cfg.RpcUrl, &ErrRPC{Cause: err})
+		}
+		e.backend = NewBackend(rc)
 	}
-	return err
+	e.vc = newRpcClient(e.backend, time.Duration(e.cfg.RpcTimeout)*time.Second)
+	return nil
 }
 
-func loadKSWallet(ksDir string, account accounts.Account) accounts.Wallet {
+func loadKSWallet(ksDir string, account accounts.Account) (*keystore.KeyStore, accounts.Wallet) {
 	n, p := keystore.StandardScryptN, keystore.StandardScryptP
 	ks := keystore.NewKeyStore(ksDir, n, p)
 
 	for _, wa := range ks.Wallets() {
 		if wa.Contains(account) {
-			return wa
+			return ks, wa
 		}
 	}
 
-	return nil
+	return nil, nil
 }
 
 // Stake returns a tx hash of staking VNT if passed condition check and tx has been send, or an error if failed.
-func (e *Election) Stake(stakeCnt string) (common.Hash, error) {
-	stake, ok := big.NewInt(0).SetString(stakeCnt, 10)
-	if !ok {
-		return emptyHash, fmt.Errorf("big.int set string error, input = %s", stakeCnt)
+// The amount is parsed by ParseAmount and must be whole VNT, or AmountAll to stake the whole VNT
+// of the balance except the gas reserve.
+func (e *Election) Stake(amount string) (common.Hash, error) {
+	return e.StakeContext(e.ctx, amount)
+}
+
+// StakeContext is the same as Stake, but uses ctx for the RPC calls.
+func (e *Election) StakeContext(ctx context.Context, amount string) (common.Hash, error) {
+	b, err := e.vc.BalanceAt(ctx, e.cfg.Sender, nil)
+	if err != nil {
+		return emptyHash, nodeError(err)
+	}
+	balance := NewAmount(b)
+
+	var stake *Amount
+	if strings.ToLower(strings.TrimSpace(amount)) == AmountAll {
+		reserve := e.cfg.Gas.Reserve
+		if reserve == nil {
+			reserve = defaultGasReserve
+		}
+		// 合约按整数个VNT抵押，向下取整
+		count := big.NewInt(0).Sub(b, reserve.Wei())
+		count.Div(count, weiPerVNT)
+		stake = stakeAmount(count)
+	} else if stake, err = ParseAmount(amount); err != nil {
+		return emptyHash, err
 	}
 
 	// 至少1个VNT
-	if stake.Cmp(big.NewInt(1)) < 0 {
-		return emptyHash, fmt.Errorf("stake = %s is less than 1 VNT", stake.String())
+	if stake.Wei().Cmp(weiPerVNT) < 0 {
+		return emptyHash, fmt.Errorf("%w, stake = %s", ErrStakeTooSmall, stake)
+	}
+	if new(big.Int).Mod(stake.Wei(), weiPerVNT).Sign() != 0 {
+		return emptyHash, fmt.Errorf("%w, stake = %s", ErrStakeNotWhole, stake)
 	}
 
 	// 抵押数不得多于自己的VNT数量
-	b, err := e.vc.BalanceAt(e.ctx, e.cfg.Sender, nil)
-	if err != nil {
-		return emptyHash, fmt.Errorf("Query balance of account:%s failed, err: %s\n", e.cfg.Sender.String(), err)
-	}
-	stakeWei := big.NewInt(0).Mul(stake, big.NewInt(1e+18))
-	if stakeWei.Cmp(b) > 0 {
-		return emptyHash, fmt.Errorf("stake more than your balance. stake = %s wei, balance = %s wei", stakeWei.String(), b.String())
+	if stake.Cmp(balance) > 0 {
+		return emptyHash, fmt.Errorf("%w, stake = %s, balance = %s", ErrInsufficientBalance, stake, balance)
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, stakeWei, 30000, big.NewInt(18000000000), "$stake")
+	unSignTx, err := e.newElectionTx(ctx, stake.Wei(), 30000, "$stake")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // Unstake returns a tx hash of staking VNT if passed condition check and tx has been send, or an error if failed.
 func (e *Election) Unstake() (common.Hash, error) {
+	return e.UnstakeContext(e.ctx)
+}
+
+// UnstakeContext is the same as Unstake, but uses ctx for the RPC calls.
+func (e *Election) UnstakeContext(ctx context.Context) (common.Hash, error) {
 	// 用户当前有抵押的VNT
-	stake, err := e.vc.StakeAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		return emptyHash, fmt.Errorf("you have no stake")
+	stake, err := e.vc.StakeAt(ctx, e.cfg.Sender)
+	if isNotFound(err) {
+		return emptyHash, ErrNoStake
+	} else if err != nil {
+		return emptyHash, nodeError(err)
 	}
 
 	if stake != nil {
-		// 距离上次抵押超过24小时
-		unstakeTime := big.NewInt(0).Add(stake.LastStakeTimeStamp, big.NewInt(vntelection.OneDay))
-		now := big.NewInt(time.Now().Unix())
-		if now.Cmp(unstakeTime) < 0 {
-			return emptyHash, fmt.Errorf("cannot unstake in 24 hours")
+		// 取回抵押后抵押记录仍然存在，数量为0
+		if stake.StakeCount == nil || stake.StakeCount.Sign() == 0 {
+			return emptyHash, ErrNoStake
+		}
+
+		// 按区块时间计算，距离上次抵押超过24小时
+		next, remaining, err := e.cooldown(ctx, stake.LastStakeTimeStamp)
+		if err != nil {
+			return emptyHash, err
+		}
+		if remaining > 0 {
+			return emptyHash, &ErrUnstakeCooldown{NextAllowed: next, Remaining: remaining}
 		}
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000, big.NewInt(18000000000), "unStake")
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "unStake")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // RegisterWitness returns tx hash of registering witness if passed condition check and tx has been send, or an error if failed.
 func (e *Election) RegisterWitness(nodeName, nodeUrl, website string) (common.Hash, error) {
+	return e.RegisterWitnessContext(e.ctx, nodeName, nodeUrl, website)
+}
+
+// RegisterWitnessContext is the same as RegisterWitness, but uses ctx for the RPC calls.
+func (e *Election) RegisterWitnessContext(ctx context.Context, nodeName, nodeUrl, website string) (common.Hash, error) {
 	// 节点名称必填，由数字和小写字母组成，长度在[3,20]区间。
 	// 网址必填，长度在[3,60]区间。
 	if err := checkCandi(nodeName, website); err != nil {
@@ -170,37 +275,42 @@ func (e *Election) RegisterWitness(nodeName, nodeUrl, website string) (common.Ha
 	}
 
 	// 名称和网址不得与其他候选人有重复，不可重复注册
-	candidates, err := e.vc.WitnessCandidates(e.ctx)
-	if err != nil && err.Error() != errNotFound {
-		return emptyHash, err
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if err != nil && !isNotFound(err) {
+		return emptyHash, nodeError(err)
 	}
 	if candidates != nil {
 		for _, c := range candidates {
 			if c.Owner != e.cfg.Sender.String() {
-				if c.Url == nodeUrl || c.Website == website {
-					return emptyHash, fmt.Errorf("candidate's name or website url is duplicated with a candidate")
+				if c.Name == nodeName || c.Url == nodeUrl || c.Website == website {
+					return emptyHash, vntelection.ErrCandiInfoDup
 				}
 			} else if c.Active {
-				return emptyHash, fmt.Errorf("candidate is already registered")
+				return emptyHash, vntelection.ErrCandiAlreadyRegistered
 			}
 		}
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000, big.NewInt(18000000000),
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000,
 		"registerWitness", []byte(nodeUrl), []byte(website), []byte(nodeName))
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // UnregisterWitness returns tx hash of unregistering witness if passed condition check and tx has been send, or an error if failed.
 func (e *Election) UnregisterWitness() (common.Hash, error) {
+	return e.UnregisterWitnessContext(e.ctx)
+}
+
+// UnregisterWitnessContext is the same as UnregisterWitness, but uses ctx for the RPC calls.
+func (e *Election) UnregisterWitnessContext(ctx context.Context) (common.Hash, error) {
 	// 账号已注册为见证人
-	candidates, err := e.vc.WitnessCandidates(e.ctx)
-	if err != nil && err.Error() != errNotFound {
-		return emptyHash, err
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if err != nil && !isNotFound(err) {
+		return emptyHash, nodeError(err)
 	}
 	if candidates != nil {
 		find := false
@@ -210,45 +320,50 @@ func (e *Election) UnregisterWitness() (common.Hash, error) {
 			}
 		}
 		if !find {
-			return emptyHash, fmt.Errorf("account: %s is not registered", e.cfg.Sender.String())
+			return emptyHash, fmt.Errorf("%w: %s", ErrNotCandidate, e.cfg.Sender.String())
 		}
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000,
-		big.NewInt(18000000000), "unregisterWitness")
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "unregisterWitness")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // Vote returns tx hash of voting for witness if passed condition check and tx has been send, or an error if failed.
 func (e *Election) Vote(witnessAddr []string) (common.Hash, error) {
+	return e.VoteContext(e.ctx, witnessAddr)
+}
+
+// VoteContext is the same as Vote, but uses ctx for the RPC calls.
+func (e *Election) VoteContext(ctx context.Context, witnessAddr []string) (common.Hash, error) {
 	// 所投候选人不得超过30人
 	if len(witnessAddr) > 30 {
-		return emptyHash, fmt.Errorf("vote too may witness, at most 30")
+		return emptyHash, ErrTooManyCandidates
 	}
 
 	// 有抵押的VNT代币
-	_, err := e.vc.StakeAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		if err.Error() == errNotFound {
-			err = fmt.Errorf("please stake before vote")
-		}
-		return emptyHash, err
+	_, err := e.vc.StakeAt(ctx, e.cfg.Sender)
+	if isNotFound(err) {
+		return emptyHash, ErrNoStake
+	} else if err != nil {
+		return emptyHash, nodeError(err)
 	}
 
 	// 距离上次投票或设置代理超过24小时
-	vote, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
-	if err != nil && err.Error() != errNotFound {
-		return emptyHash, err
+	vote, err := e.vc.VoteAt(ctx, e.cfg.Sender)
+	if err != nil && !isNotFound(err) {
+		return emptyHash, nodeError(err)
 	}
 	if vote != nil {
-		nextVoteTime := big.NewInt(0).Add(vote.LastVoteTimeStamp, big.NewInt(vntelection.OneDay))
-		now := big.NewInt(time.Now().Unix())
-		if now.Cmp(nextVoteTime) < 0 {
-			return emptyHash, fmt.Errorf("cannot vote or set proxy twice within 24 hours")
+		next, remaining, err := e.cooldown(ctx, vote.LastVoteTimeStamp)
+		if err != nil {
+			return emptyHash, err
+		}
+		if remaining > 0 {
+			return emptyHash, &ErrVoteCooldown{NextAllowed: next, Remaining: remaining}
 		}
 	}
 
@@ -261,175 +376,212 @@ func (e *Election) Vote(witnessAddr []string) (common.Hash, error) {
 		witnesses[i] = common.HexToAddress(w)
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 60000,
-		big.NewInt(18000000000), "voteWitnesses", witnesses)
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 60000, "voteWitnesses", witnesses)
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // CancelVote returns tx hash of cancellation vote for witness if passed condition check and tx has been send, or an error if failed.
 func (e *Election) CancelVote() (common.Hash, error) {
+	return e.CancelVoteContext(e.ctx)
+}
+
+// CancelVoteContext is the same as CancelVote, but uses ctx for the RPC calls.
+func (e *Election) CancelVoteContext(ctx context.Context) (common.Hash, error) {
 	// 未设置代理、被投票的见证人列表为空
-	vote, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		if err.Error() == errNotFound {
-			return emptyHash, fmt.Errorf("not vote before")
-		}
-		return emptyHash, err
+	vote, err := e.vc.VoteAt(ctx, e.cfg.Sender)
+	if isNotFound(err) {
+		return emptyHash, ErrNotVoted
+	} else if err != nil {
+		return emptyHash, nodeError(err)
 	}
 	if vote != nil {
 		if vote.Proxy != emptyAddr {
-			return emptyHash, fmt.Errorf("please use cancelProxy to unset your proxy")
+			return emptyHash, fmt.Errorf("%w, please use cancelProxy to unset your proxy", ErrHasProxy)
 		} else if len(vote.VoteCandidates) == 0 {
-			return emptyHash, fmt.Errorf("you didn't vote for any witness")
+			return emptyHash, ErrNotVoted
 		}
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000,
-		big.NewInt(18000000000), "cancelVote")
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "cancelVote")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // StartProxy returns tx hash of becoming a vote proxy if passed condition check and tx has been send, or an error if failed.
 func (e *Election) StartProxy() (common.Hash, error) {
+	return e.StartProxyContext(e.ctx)
+}
+
+// StartProxyContext is the same as StartProxy, but uses ctx for the RPC calls.
+func (e *Election) StartProxyContext(ctx context.Context) (common.Hash, error) {
 	// 已经开启了代理功能，不可重复开启。
 	// 已经设置了代理人，不可开启代理功能。
-	voter, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
-	if err != nil && err.Error() != errNotFound {
-		return emptyHash, err
+	voter, err := e.vc.VoteAt(ctx, e.cfg.Sender)
+	if err != nil && !isNotFound(err) {
+		return emptyHash, nodeError(err)
 	}
 	if voter != nil {
 		if voter.IsProxy {
-			return emptyHash, fmt.Errorf("you are vote proxy, no need start proxy again")
+			return emptyHash, fmt.Errorf("%w, no need start proxy again", ErrAlreadyProxy)
 		} else if voter.Proxy != emptyAddr {
-			return emptyHash, fmt.Errorf("can not become a vote proxy, when you have a vote proxy")
+			return emptyHash, fmt.Errorf("%w, can not become a vote proxy", ErrHasProxy)
 		}
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000,
-		big.NewInt(18000000000), "startProxy")
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "startProxy")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // StopProxy returns tx hash of back to a normal voter if passed condition check and tx has been send, or an error if failed.
 func (e *Election) StopProxy() (common.Hash, error) {
+	return e.StopProxyContext(e.ctx)
+}
+
+// StopProxyContext is the same as StopProxy, but uses ctx for the RPC calls.
+func (e *Election) StopProxyContext(ctx context.Context) (common.Hash, error) {
 	// 是代理人
-	voter, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		if err.Error() == errNotFound {
-			return emptyHash, fmt.Errorf("you are not a vote proxy, no need stop proxy")
-		}
-		return emptyHash, err
+	voter, err := e.vc.VoteAt(ctx, e.cfg.Sender)
+	if isNotFound(err) {
+		return emptyHash, fmt.Errorf("%w, no need stop proxy", ErrNotProxy)
+	} else if err != nil {
+		return emptyHash, nodeError(err)
 	}
 	if voter != nil {
 		if !voter.IsProxy {
-			return emptyHash, fmt.Errorf("you are not a vote proxy, no need stop proxy")
+			return emptyHash, fmt.Errorf("%w, no need stop proxy", ErrNotProxy)
 		}
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000,
-		big.NewInt(18000000000), "stopProxy")
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "stopProxy")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // SetProxy returns tx hash of setting vote proxy if passed condition check and tx has been send, or an error if failed.
 func (e *Election) SetProxy(addr string) (common.Hash, error) {
+	return e.SetProxyContext(e.ctx, addr)
+}
+
+// SetProxyContext is the same as SetProxy, but uses ctx for the RPC calls.
+func (e *Election) SetProxyContext(ctx context.Context, addr string) (common.Hash, error) {
 	proxyAddr := common.HexToAddress(addr)
 	// 不可将自己设置为自己的代理人
 	if e.cfg.Sender.String() == proxyAddr.String() {
-		return emptyHash, fmt.Errorf("can not set self as your proxy")
+		return emptyHash, ErrProxySelf
 	}
 	// 有抵押的VNT代币
-	_, err := e.vc.StakeAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		if err.Error() == errNotFound {
-			err = fmt.Errorf("please stake before vote")
-		}
-		return emptyHash, err
+	_, err := e.vc.StakeAt(ctx, e.cfg.Sender)
+	if isNotFound(err) {
+		return emptyHash, ErrNoStake
+	} else if err != nil {
+		return emptyHash, nodeError(err)
 	}
 
-	vote, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
-	if err != nil && err.Error() != errNotFound {
-		return emptyHash, err
+	vote, err := e.vc.VoteAt(ctx, e.cfg.Sender)
+	if err != nil && !isNotFound(err) {
+		return emptyHash, nodeError(err)
 	}
 	if vote != nil {
 		// 自己是代理人不可设置他人为代理
 		if vote.IsProxy {
-			return emptyHash, fmt.Errorf("can not set proxy when you are a proxy")
+			return emptyHash, fmt.Errorf("%w, can not set proxy", ErrAlreadyProxy)
 		}
 
 		// 距离上次投票或设置代理超过24小时
-		nextVoteTime := big.NewInt(0).Add(vote.LastVoteTimeStamp, big.NewInt(vntelection.OneDay))
-		now := big.NewInt(time.Now().Unix())
-		if now.Cmp(nextVoteTime) < 0 {
-			return emptyHash, fmt.Errorf("cannot vote or set proxy twice within 24 hours")
+		next, remaining, err := e.cooldown(ctx, vote.LastVoteTimeStamp)
+		if err != nil {
+			return emptyHash, err
+		}
+		if remaining > 0 {
+			return emptyHash, &ErrVoteCooldown{NextAllowed: next, Remaining: remaining}
 		}
 	}
 
 	// 要设置的代理人必须是代理
-	proxy, err := e.vc.VoteAt(e.ctx, proxyAddr)
-	if err != nil && err.Error() != errNotFound {
-		return emptyHash, fmt.Errorf("%s is not a proxy", addr)
+	proxy, err := e.vc.VoteAt(ctx, proxyAddr)
+	if err != nil && !isNotFound(err) {
+		return emptyHash, nodeError(err)
 	}
 	if proxy != nil && !proxy.IsProxy {
-		return emptyHash, fmt.Errorf("%s is not a proxy", addr)
+		return emptyHash, fmt.Errorf("%w: %s", ErrNotProxy, addr)
 	}
 
 	// 需要转换为地址
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000,
-		big.NewInt(18000000000), "setProxy", proxyAddr)
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "setProxy", proxyAddr)
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 // CancelProxy returns tx hash of cancel setting vote proxy if passed condition check and tx has been send, or an error if failed.
 func (e *Election) CancelProxy() (common.Hash, error) {
+	return e.CancelProxyContext(e.ctx)
+}
+
+// CancelProxyContext is the same as CancelProxy, but uses ctx for the RPC calls.
+func (e *Election) CancelProxyContext(ctx context.Context) (common.Hash, error) {
 	// 设置过代理人
-	voter, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
+	voter, err := e.vc.VoteAt(ctx, e.cfg.Sender)
+	if isNotFound(err) {
+		return emptyHash, fmt.Errorf("%w, no need cancel proxy", ErrNoProxy)
+	} else if err != nil {
+		return emptyHash, nodeError(err)
+	}
+	if voter != nil && voter.Proxy == emptyAddr {
+		return emptyHash, fmt.Errorf("%w, no need cancel proxy", ErrNoProxy)
+	}
+
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "cancelProxy")
 	if err != nil {
-		if err.Error() == errNotFound {
-			return emptyHash, fmt.Errorf("you have no proxy, no need cancel proxy")
-		}
 		return emptyHash, err
 	}
-	if voter != nil && voter.Proxy == emptyAddr {
-		return emptyHash, fmt.Errorf("you have no proxy, no need cancel proxy")
+
+	return e.signAndSendTx(ctx, unSignTx)
+}
+
+// ExtractBounty returns tx hash of extracting the bounty of witness if passed condition check and tx has been send, or an error if failed.
+func (e *Election) ExtractBounty() (common.Hash, error) {
+	return e.ExtractBountyContext(e.ctx)
+}
+
+// ExtractBountyContext is the same as ExtractBounty, but uses ctx for the RPC calls.
+func (e *Election) ExtractBountyContext(ctx context.Context) (common.Hash, error) {
+	// 账号是见证人候选人，距离上次提取超过24小时，可提取的激励不少于1000VNT
+	if _, err := e.QueryExtractableBountyContext(ctx); err != nil {
+		return emptyHash, err
 	}
 
-	unSignTx, err := e.vc.NewElectionTx(e.ctx, e.cfg.Sender, common.Big0, 30000,
-		big.NewInt(18000000000), "cancelProxy")
+	unSignTx, err := e.newElectionTx(ctx, common.Big0, 30000, "extractOwnBounty")
 	if err != nil {
 		return emptyHash, err
 	}
 
-	return e.signAndSendTx(unSignTx)
+	return e.signAndSendTx(ctx, unSignTx)
 }
 
 func checkCandi(name string, website string) error {
 	// length check
 	if len(name) < 3 || len(name) > 20 {
-		return fmt.Errorf("the length of candidate's name should between [3, 20]")
+		return vntelection.ErrCandiNameLenInvalid
 	}
 	if len(website) < 3 || len(website) > 60 {
-		return fmt.Errorf("the length of candidate's website url should between [3, 60]")
+		return vntelection.ErrCandiUrlLenInvalid
 	}
 
 	digitalAndLower := func(s string) bool {
@@ -441,20 +593,71 @@ func checkCandi(name string, website string) error {
 		return true
 	}
 	if !digitalAndLower(name) {
-		return fmt.Errorf("andidate's name should consist of digits and lowercase letters")
+		return vntelection.ErrCandiNameInvalid
 	}
 
 	return nil
 }
 
 // signAndSendTx returns tx hash if sign and send transaction success.
-func (e *Election) signAndSendTx(unSignTx *types.Transaction) (common.Hash, error) {
-	tx, err := e.wallet.SignTxWithPassphrase(e.account, e.cfg.Password, unSignTx, big.NewInt(int64(e.cfg.ChainID)))
+func (e *Election) signAndSendTx(ctx context.Context, unSignTx *types.Transaction) (common.Hash, error) {
+	defer e.releaseNonce()
+	if e.handleTx != nil {
+		return e.handleTx(unSignTx)
+	}
+
+	tx, err := e.signTx(unSignTx, e.cfg.ChainID)
 	if err != nil {
 		return emptyHash, err
 	}
-	if err := e.vc.SendTransaction(e.ctx, tx); err != nil {
-		return emptyHash, fmt.Errorf("send transaction occur error: %s", err)
+	if err := e.vc.SendTransaction(ctx, tx); err != nil {
+		e.log.Debug("Send transaction failed", "hash", tx.Hash(), "err", err)
+		return emptyHash, nodeError(err)
 	}
+	e.log.Info("Sent transaction", "hash", tx.Hash(), "nonce", tx.Nonce())
+	e.commitNonce(tx.Nonce())
+	e.lastTx = tx
 	return tx.Hash(), nil
 }
+
+// signTx returns the transaction signed by the account of config, using the injected
+// signer or the wallet in keystore.
+func (e *Election) signTx(unSignTx *types.Transaction, chainID int) (*types.Transaction, error) {
+	if e.watchOnly {
+		return nil, ErrWatchOnly
+	}
+	signer := e.signer
+	if signer == nil {
+		if err := e.loadWallet(); err != nil {
+			return nil, err
+		}
+		signer = e.wallet
+	}
+	id := big.NewInt(int64(chainID))
+	e.log.Debug("Sign transaction", "account", e.account.Address, "nonce", unSignTx.Nonce())
+
+	// 钱包解锁后在超时时间内复用，超时后自动锁定；注入的签名者可能无需密码
+	if e.cfg.UnlockTimeout > 0 || e.signer != nil {
+		if tx, err := signer.SignTx(e.account, unSignTx, id); err != keystore.ErrLocked {
+			return tx, err
+		}
+	}
+
+	pp, err := e.passwordProvider()
+	if err != nil {
+		return nil, err
+	}
+	password, err := pp.Password(e.account.Address)
+	if err != nil {
+		return nil, err
+	}
+	if e.cfg.UnlockTimeout <= 0 || e.ks == nil {
+		return signer.SignTxWithPassphrase(e.account, password, unSignTx, id)
+	}
+
+	timeout := time.Duration(e.cfg.UnlockTimeout) * time.Second
+	if err := e.ks.TimedUnlock(e.account, password, timeout); err != nil {
+		return nil, err
+	}
+	return signer.SignTx(e.account, unSignTx, id)
+}
@@ -0,0 +1,41 @@
+package electtest
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/vntchain/go-vnt/accounts"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/crypto"
+)
+
+// Account is an account with its private key in memory. It signs transactions without
+// password, so it can be used as the signer of elect by elect.WithSigner.
+type Account struct {
+	Address common.Address
+	Key     *ecdsa.PrivateKey
+}
+
+// NewAccount returns an account of a random key.
+func NewAccount() *Account {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		panic(fmt.Sprintf("generate key error: %s", err))
+	}
+	return &Account{Address: crypto.PubkeyToAddress(key.PublicKey), Key: key}
+}
+
+// SignTx signs the transaction by the key of the account.
+func (a *Account) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	if account.Address != a.Address {
+		return nil, fmt.Errorf("unknown account: %s", account.Address.String())
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), a.Key)
+}
+
+// SignTxWithPassphrase is the same as SignTx, the passphrase is ignored.
+func (a *Account) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return a.SignTx(account, tx, chainID)
+}
@@ -0,0 +1,312 @@
+package electtest
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/core/state"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/core/vm/election"
+	"github.com/vntchain/go-vnt/rlp"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// API is the RPC service of Node in the core namespace, it serves the methods used by
+// elect in the same format as go-vnt. The state of any block number except pending is
+// the state of the latest block.
+type API struct {
+	n *Node
+}
+
+// CallArgs is the arguments of core_call and core_estimateGas.
+type CallArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Uint64  `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+}
+
+// RPCTransaction is the RPC representation of a transaction.
+type RPCTransaction struct {
+	BlockHash        *common.Hash    `json:"blockHash"`
+	BlockNumber      *hexutil.Big    `json:"blockNumber"`
+	From             common.Address  `json:"from"`
+	Gas              hexutil.Uint64  `json:"gas"`
+	GasPrice         *hexutil.Big    `json:"gasPrice"`
+	Hash             common.Hash     `json:"hash"`
+	Input            hexutil.Bytes   `json:"input"`
+	Nonce            hexutil.Uint64  `json:"nonce"`
+	To               *common.Address `json:"to"`
+	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
+	Value            *hexutil.Big    `json:"value"`
+	V                *hexutil.Big    `json:"v"`
+	R                *hexutil.Big    `json:"r"`
+	S                *hexutil.Big    `json:"s"`
+}
+
+// stateOf returns the state of the block number, the caller must hold the lock.
+func (api *API) stateOf(blockNr rpc.BlockNumber) *state.StateDB {
+	if blockNr == rpc.PendingBlockNumber {
+		return api.n.pendingState()
+	}
+	return api.n.state
+}
+
+// GetBalance returns the balance of the account in wei.
+func (api *API) GetBalance(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (*hexutil.Big, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	return (*hexutil.Big)(api.stateOf(blockNr).GetBalance(address)), nil
+}
+
+// GetTransactionCount returns the nonce of the account.
+func (api *API) GetTransactionCount(ctx context.Context, address common.Address, blockNr rpc.BlockNumber) (hexutil.Uint64, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	if blockNr == rpc.PendingBlockNumber {
+		return hexutil.Uint64(api.n.pendingNonce(address)), nil
+	}
+	return hexutil.Uint64(api.n.state.GetNonce(address)), nil
+}
+
+// GasPrice returns the suggested gas price in wei.
+func (api *API) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	return (*hexutil.Big)(api.n.gasPrice), nil
+}
+
+// Call runs the message against a copy of the state, and returns nothing as the election
+// contract has no output.
+func (api *API) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	st := api.stateOf(blockNr).Copy()
+	if err := api.n.call(st, args.From, args.To, args.Value.ToInt(), args.Data, api.n.now); err != nil {
+		return nil, err
+	}
+	return hexutil.Bytes{}, nil
+}
+
+// EstimateGas returns the gas used by the message against the pending state, or the error
+// of the election contract if the message would fail.
+func (api *API) EstimateGas(ctx context.Context, args CallArgs) (hexutil.Uint64, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	st := api.n.pendingState()
+	if err := api.n.call(st, args.From, args.To, args.Value.ToInt(), args.Data, api.n.now); err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(intrinsicGas(args.Data)), nil
+}
+
+// SendRawTransaction decodes the rlp encoded signed transaction and sends it.
+func (api *API) SendRawTransaction(ctx context.Context, encodedTx hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.n.SendTransaction(tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// GetTransactionByHash returns the transaction of hash, or nil if not found.
+func (api *API) GetTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	lookup, ok := api.n.txs[hash]
+	if !ok {
+		return nil, nil
+	}
+	return newRPCTransaction(lookup), nil
+}
+
+// GetTransactionReceipt returns the receipt of the mined transaction, or nil if the
+// transaction is not found or pending.
+func (api *API) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	lookup, ok := api.n.txs[hash]
+	if !ok || lookup.block == nil {
+		return nil, nil
+	}
+	receipt := api.n.receipts[hash]
+	return map[string]interface{}{
+		"blockHash":         lookup.block.Hash(),
+		"blockNumber":       hexutil.Uint64(lookup.block.NumberU64()),
+		"transactionHash":   hash,
+		"transactionIndex":  hexutil.Uint64(lookup.index),
+		"from":              lookup.from,
+		"to":                lookup.tx.To(),
+		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
+		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
+		"contractAddress":   nil,
+		"logs":              receipt.Logs,
+		"logsBloom":         receipt.Bloom,
+		"status":            hexutil.Uint(receipt.Status),
+	}, nil
+}
+
+// GetBlockByNumber returns the block of number, or nil if not found. The transactions are
+// included in full if fullTx is true, or as hashes otherwise.
+func (api *API) GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	number := int64(blockNr)
+	if blockNr == rpc.LatestBlockNumber || blockNr == rpc.PendingBlockNumber {
+		number = int64(len(api.n.blocks) - 1)
+	}
+	if number < 0 || number >= int64(len(api.n.blocks)) {
+		return nil, nil
+	}
+	return api.n.marshalBlock(api.n.blocks[number], fullTx), nil
+}
+
+// GetBlockByHash returns the block of hash, or nil if not found.
+func (api *API) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	for _, b := range api.n.blocks {
+		if b.Hash() == hash {
+			return api.n.marshalBlock(b, fullTx), nil
+		}
+	}
+	return nil, nil
+}
+
+// GetStake returns the stake of the account, or nil if the account never staked.
+func (api *API) GetStake(ctx context.Context, address common.Address) (*rpc.Stake, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	st := election.GetStake(api.n.state, address)
+	if st == nil || st.Owner == (common.Address{}) {
+		return nil, nil
+	}
+	return &rpc.Stake{
+		Owner:              st.Owner,
+		StakeCount:         st.StakeCount,
+		LastStakeTimeStamp: st.TimeStamp,
+	}, nil
+}
+
+// GetVoter returns the vote information of the account, or nil if the account never voted.
+func (api *API) GetVoter(ctx context.Context, address common.Address) (*rpc.Voter, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	v := election.GetVoter(api.n.state, address)
+	if v == nil || v.Owner == (common.Address{}) {
+		return nil, nil
+	}
+	return &rpc.Voter{
+		Owner:             v.Owner,
+		IsProxy:           v.IsProxy,
+		ProxyVoteCount:    v.ProxyVoteCount,
+		Proxy:             v.Proxy,
+		LastVoteCount:     v.LastVoteCount,
+		LastVoteTimeStamp: v.TimeStamp,
+		VoteCandidates:    v.VoteCandidates,
+	}, nil
+}
+
+// GetAllCandidates returns the witness candidates sorted by votes, or nil if there is no
+// candidate.
+func (api *API) GetAllCandidates(ctx context.Context) ([]rpc.Candidate, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	list := election.GetAllCandidates(api.n.state, true)
+	if len(list) == 0 {
+		return nil, nil
+	}
+
+	candidates := make([]rpc.Candidate, len(list))
+	for i, c := range list {
+		candidates[i] = rpc.Candidate{
+			Owner:           c.Owner.String(),
+			Name:            string(c.Name),
+			Active:          c.Active,
+			Url:             string(c.Url),
+			VoteCount:       (*hexutil.Big)(c.VoteCount),
+			TotalBounty:     (*hexutil.Big)(c.TotalBounty),
+			ExtractedBounty: (*hexutil.Big)(c.ExtractedBounty),
+			LastExtractTime: (*hexutil.Big)(c.LastExtractTime),
+			Website:         string(c.Website),
+		}
+	}
+	return candidates, nil
+}
+
+// GetRestVNTBounty returns the rest VNT bounty in wei.
+func (api *API) GetRestVNTBounty(ctx context.Context) (*big.Int, error) {
+	api.n.mu.Lock()
+	defer api.n.mu.Unlock()
+	if rest := election.QueryRestVNTBounty(api.n.state.Copy()); rest != nil {
+		return rest, nil
+	}
+	return nil, errors.New("can not get rest VNT bounty data")
+}
+
+// marshalBlock returns the RPC representation of the block.
+func (n *Node) marshalBlock(b *types.Block, fullTx bool) map[string]interface{} {
+	head := b.Header()
+	txs := make([]interface{}, len(b.Transactions()))
+	for i, tx := range b.Transactions() {
+		if fullTx {
+			txs[i] = newRPCTransaction(n.txs[tx.Hash()])
+		} else {
+			txs[i] = tx.Hash()
+		}
+	}
+	return map[string]interface{}{
+		"number":           (*hexutil.Big)(head.Number),
+		"hash":             b.Hash(),
+		"parentHash":       head.ParentHash,
+		"logsBloom":        head.Bloom,
+		"stateRoot":        head.Root,
+		"producer":         head.Coinbase,
+		"difficulty":       (*hexutil.Big)(head.Difficulty),
+		"extraData":        hexutil.Bytes(head.Extra),
+		"size":             hexutil.Uint64(b.Size()),
+		"gasLimit":         hexutil.Uint64(head.GasLimit),
+		"gasUsed":          hexutil.Uint64(head.GasUsed),
+		"timestamp":        (*hexutil.Big)(head.Time),
+		"transactionsRoot": head.TxHash,
+		"receiptsRoot":     head.ReceiptHash,
+		"witnesses":        head.Witnesses,
+		"signature":        hexutil.Bytes(head.Signature),
+		"transactions":     txs,
+	}
+}
+
+// newRPCTransaction returns the RPC representation of the transaction, the block fields
+// are nil if it's pending.
+func newRPCTransaction(lookup *txLookup) *RPCTransaction {
+	tx := lookup.tx
+	v, r, s := tx.RawSignatureValues()
+	ret := &RPCTransaction{
+		From:     lookup.from,
+		Gas:      hexutil.Uint64(tx.Gas()),
+		GasPrice: (*hexutil.Big)(tx.GasPrice()),
+		Hash:     tx.Hash(),
+		Input:    hexutil.Bytes(tx.Data()),
+		Nonce:    hexutil.Uint64(tx.Nonce()),
+		To:       tx.To(),
+		Value:    (*hexutil.Big)(tx.Value()),
+		V:        (*hexutil.Big)(v),
+		R:        (*hexutil.Big)(r),
+		S:        (*hexutil.Big)(s),
+	}
+	if lookup.block != nil {
+		hash := lookup.block.Hash()
+		ret.BlockHash = &hash
+		ret.BlockNumber = (*hexutil.Big)(lookup.block.Number())
+		ret.TransactionIndex = hexutil.Uint(lookup.index)
+	}
+	return ret
+}
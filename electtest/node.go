@@ -0,0 +1,466 @@
+// Package electtest provides an in-process fake Hubble node for testing and demos of elect.
+//
+// The Node serves the RPC methods used by elect with a go-vnt rpc.Server, and applies
+// the sent transactions by running the election contract of go-vnt against an in-memory
+// state, with a clock controlled by the test:
+//
+//	node := electtest.NewNode(2)
+//	defer node.Close()
+//	voter := electtest.NewAccount()
+//	node.Fund(voter.Address, electtest.VNT(100))
+//
+//	cfg := &elect.Config{Sender: voter.Address, ChainID: 2}
+//	e, err := elect.NewElectionWithConfig(cfg, elect.WithClient(elect.NewBackend(node.Dial())),
+//		elect.WithSigner(voter), elect.WithClock(node.Now))
+package electtest
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vntchain/go-vnt/accounts/abi"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/state"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/core/vm/election"
+	inter "github.com/vntchain/go-vnt/core/vm/interface"
+	"github.com/vntchain/go-vnt/params"
+	"github.com/vntchain/go-vnt/rpc"
+	"github.com/vntchain/go-vnt/vntdb"
+)
+
+// DefaultGasPrice is the gas price suggested by Node, 18 Gwei.
+var DefaultGasPrice = big.NewInt(18000000000)
+
+// DefaultTime is the initial time of the clock of Node.
+var DefaultTime = time.Date(2019, 6, 25, 0, 0, 0, 0, time.UTC)
+
+var (
+	contractAddr = common.HexToAddress(election.ContractAddr)
+	weiPerVNT    = big.NewInt(1e+18)
+
+	// stakeMethod is the payable stake method of Hubble's election contract, which stakes
+	// the value of transaction.
+	stakeMethod = abi.Method{Name: "$stake"}
+	electionABI abi.ABI
+)
+
+func init() {
+	var err error
+	if electionABI, err = abi.JSON(strings.NewReader(election.AbiJSON)); err != nil {
+		panic(fmt.Sprintf("parse election abi error: %s", err))
+	}
+}
+
+// VNT returns n VNT in wei.
+func VNT(n int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(n), weiPerVNT)
+}
+
+// Node is an in-process fake Hubble node. Transactions are mined into a new block as soon
+// as they are sent, unless auto mining is disabled by SetAutoMine. Each block has the time
+// of the clock of Node, and the clock only moves by SetTime and AdvanceTime.
+type Node struct {
+	mu       sync.Mutex
+	chainID  *big.Int
+	signer   types.Signer
+	db       state.Database
+	state    *state.StateDB // state of the latest block
+	now      time.Time
+	autoMine bool
+	gasPrice *big.Int
+
+	blocks   []*types.Block
+	pending  []*types.Transaction
+	txs      map[common.Hash]*txLookup
+	receipts map[common.Hash]*types.Receipt
+
+	server *rpc.Server
+}
+
+// txLookup is the location of a transaction, the block is nil if it's pending.
+type txLookup struct {
+	tx    *types.Transaction
+	from  common.Address
+	block *types.Block
+	index int
+}
+
+// NewNode returns a Node of the chain, with the genesis block mined at DefaultTime.
+func NewNode(chainID int64) *Node {
+	db := state.NewDatabase(vntdb.NewMemDatabase())
+	st, _ := state.New(common.Hash{}, db)
+	n := &Node{
+		chainID:  big.NewInt(chainID),
+		signer:   types.NewEIP155Signer(big.NewInt(chainID)),
+		db:       db,
+		state:    st,
+		now:      DefaultTime,
+		autoMine: true,
+		gasPrice: DefaultGasPrice,
+		txs:      make(map[common.Hash]*txLookup),
+		receipts: make(map[common.Hash]*types.Receipt),
+		server:   rpc.NewServer(),
+	}
+	n.commitBlock(nil, nil)
+
+	if err := n.server.RegisterName("core", &API{n: n}); err != nil {
+		panic(fmt.Sprintf("register core api error: %s", err))
+	}
+	return n
+}
+
+// Dial returns an in-process RPC client of the node.
+func (n *Node) Dial() *rpc.Client {
+	return rpc.DialInProc(n.server)
+}
+
+// Handler returns the HTTP handler of the RPC server, it's used to serve the node over
+// HTTP, such as httptest.NewServer(node.Handler()).
+func (n *Node) Handler() http.Handler {
+	return n.server
+}
+
+// Close stops the RPC server.
+func (n *Node) Close() {
+	n.server.Stop()
+}
+
+// Now returns the time of the clock, it can be used as the clock of elect.
+func (n *Node) Now() time.Time {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.now
+}
+
+// SetTime sets the time of the clock, which is the time of the following blocks.
+func (n *Node) SetTime(t time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.now = t
+}
+
+// AdvanceTime moves the clock forward by d.
+func (n *Node) AdvanceTime(d time.Duration) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.now = n.now.Add(d)
+}
+
+// SetAutoMine sets whether to mine the transactions as soon as they are sent. If it's
+// disabled, the transactions stay pending until Mine is called.
+func (n *Node) SetAutoMine(auto bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.autoMine = auto
+}
+
+// SetGasPrice sets the gas price suggested by node.
+func (n *Node) SetGasPrice(price *big.Int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.gasPrice = price
+}
+
+// Fund adds wei to the balance of the account, and mines a block.
+func (n *Node) Fund(addr common.Address, wei *big.Int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.state.AddBalance(addr, wei)
+	n.commitBlock(nil, nil)
+}
+
+// GrantBounty grants the bounty in wei to the witness candidate from the rest VNT bounty,
+// like the bounty granted by consensus engine, and mines a block.
+func (n *Node) GrantBounty(candidate common.Address, wei *big.Int) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	election.QueryRestVNTBounty(n.state)
+	if _, err := election.GrantBounty(n.state, wei); err != nil {
+		return err
+	}
+	if err := election.AddCandidatesBounty(n.state, map[common.Address]*big.Int{candidate: wei}); err != nil {
+		return err
+	}
+	n.commitBlock(nil, nil)
+	return nil
+}
+
+// Mine mines the pending transactions into a new block, and returns the block.
+func (n *Node) Mine() *types.Block {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.mine()
+}
+
+// Rewind drops the blocks after the block of number and their transactions, and resets the
+// state to the block, like a reorg of the chain before mining other blocks.
+func (n *Node) Rewind(number uint64) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if number >= uint64(len(n.blocks)) {
+		return fmt.Errorf("block %d not found", number)
+	}
+	st, err := state.New(n.blocks[number].Root(), n.db)
+	if err != nil {
+		return err
+	}
+	for _, b := range n.blocks[number+1:] {
+		for _, tx := range b.Transactions() {
+			delete(n.txs, tx.Hash())
+			delete(n.receipts, tx.Hash())
+		}
+	}
+	n.blocks = n.blocks[:number+1]
+	n.state = st
+	return nil
+}
+
+// BlockNumber returns the number of the latest block.
+func (n *Node) BlockNumber() uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.latest().NumberU64()
+}
+
+// Balance returns the balance of the account in wei at the latest block.
+func (n *Node) Balance(addr common.Address) *big.Int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.state.GetBalance(addr)
+}
+
+// SendTransaction adds the signed transaction to the pending transactions, it replaces the
+// pending transaction of the same sender and nonce if its gas price is higher. The
+// transaction is mined at once if auto mining is enabled.
+func (n *Node) SendTransaction(tx *types.Transaction) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	from, err := types.Sender(n.signer, tx)
+	if err != nil {
+		return fmt.Errorf("invalid sender: %s", err)
+	}
+	if tx.Nonce() < n.state.GetNonce(from) {
+		return errors.New("nonce too low")
+	}
+	if tx.Nonce() > n.pendingNonce(from) {
+		return errors.New("nonce too high")
+	}
+	cost := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
+	if cost.Add(cost, tx.Value()).Cmp(n.state.GetBalance(from)) > 0 {
+		return errors.New("insufficient funds for gas * price + value")
+	}
+	if tx.Gas() < intrinsicGas(tx.Data()) {
+		return errors.New("intrinsic gas too low")
+	}
+	if _, ok := n.txs[tx.Hash()]; ok {
+		return fmt.Errorf("known transaction: %x", tx.Hash())
+	}
+
+	replaced := false
+	for i, p := range n.pending {
+		if n.txs[p.Hash()].from != from || p.Nonce() != tx.Nonce() {
+			continue
+		}
+		if tx.GasPrice().Cmp(p.GasPrice()) <= 0 {
+			return errors.New("replacement transaction underpriced")
+		}
+		delete(n.txs, p.Hash())
+		n.pending[i] = tx
+		replaced = true
+	}
+	if !replaced {
+		n.pending = append(n.pending, tx)
+	}
+	n.txs[tx.Hash()] = &txLookup{tx: tx, from: from}
+
+	if n.autoMine {
+		n.mine()
+	}
+	return nil
+}
+
+// mine applies the pending transactions in nonce order of each sender, and commits them
+// into a new block.
+func (n *Node) mine() *types.Block {
+	var (
+		txs      []*types.Transaction
+		receipts []*types.Receipt
+		rest     []*types.Transaction
+		gasUsed  uint64
+	)
+	for applied := true; applied; {
+		applied = false
+		rest = rest[:0]
+		for _, tx := range n.pending {
+			from := n.txs[tx.Hash()].from
+			if tx.Nonce() != n.state.GetNonce(from) {
+				rest = append(rest, tx)
+				continue
+			}
+			receipt := n.applyTx(n.state, tx, from, n.now)
+			gasUsed += receipt.GasUsed
+			receipt.CumulativeGasUsed = gasUsed
+			txs = append(txs, tx)
+			receipts = append(receipts, receipt)
+			applied = true
+		}
+		n.pending = append([]*types.Transaction(nil), rest...)
+	}
+	return n.commitBlock(txs, receipts)
+}
+
+// commitBlock commits the state and appends a block of the transactions.
+func (n *Node) commitBlock(txs []*types.Transaction, receipts []*types.Receipt) *types.Block {
+	root, err := n.state.Commit(true)
+	if err != nil {
+		panic(fmt.Sprintf("commit state error: %s", err))
+	}
+	header := &types.Header{
+		Root:       root,
+		Difficulty: big.NewInt(1),
+		Number:     big.NewInt(int64(len(n.blocks))),
+		GasLimit:   params.GenesisGasLimit,
+		Time:       big.NewInt(n.now.Unix()),
+		Extra:      []byte{},
+		Witnesses:  []common.Address{},
+		Signature:  []byte{},
+	}
+	for _, r := range receipts {
+		header.GasUsed += r.GasUsed
+	}
+	if len(n.blocks) > 0 {
+		header.ParentHash = n.latest().Hash()
+	}
+	block := types.NewBlock(header, txs, receipts)
+	n.blocks = append(n.blocks, block)
+
+	for i, tx := range txs {
+		n.txs[tx.Hash()].block = block
+		n.txs[tx.Hash()].index = i
+		receipts[i].TxHash = tx.Hash()
+		n.receipts[tx.Hash()] = receipts[i]
+	}
+	return block
+}
+
+// applyTx applies the transaction to the state, the gas is always charged, and the state
+// changed by the transaction is reverted if it failed.
+func (n *Node) applyTx(st *state.StateDB, tx *types.Transaction, from common.Address, now time.Time) *types.Receipt {
+	gas := intrinsicGas(tx.Data())
+	st.SetNonce(from, tx.Nonce()+1)
+	st.SubBalance(from, new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(gas)))
+
+	snapshot := st.Snapshot()
+	err := n.call(st, from, tx.To(), tx.Value(), tx.Data(), now)
+	if err != nil {
+		st.RevertToSnapshot(snapshot)
+	}
+	receipt := types.NewReceipt(nil, err != nil, 0)
+	receipt.GasUsed = gas
+	receipt.Logs = []*types.Log{}
+	return receipt
+}
+
+// call runs the message against the state, it calls the election contract if to is the
+// contract address, or transfers value otherwise.
+func (n *Node) call(st *state.StateDB, from common.Address, to *common.Address, value *big.Int, data []byte, now time.Time) error {
+	if value == nil {
+		value = common.Big0
+	}
+	if st.GetBalance(from).Cmp(value) < 0 {
+		return errors.New("insufficient balance for transfer")
+	}
+	if to == nil {
+		return errors.New("contract creation is not supported")
+	}
+	if *to != contractAddr {
+		st.SubBalance(from, value)
+		st.AddBalance(*to, value)
+		return nil
+	}
+
+	if len(data) < 4 {
+		return errors.New("call election contract err: method doesn't exist")
+	}
+	ctx := &chainContext{state: st, origin: from, time: big.NewInt(now.Unix())}
+	if string(data[:4]) == string(stakeMethod.Id()) {
+		// The election contract of go-vnt stakes the count of VNT given by argument and
+		// subtracts it from balance, instead of the value of transaction.
+		if new(big.Int).Mod(value, weiPerVNT).Sign() != 0 {
+			return errors.New("stake must be whole VNT")
+		}
+		input, err := electionABI.Pack("stake", new(big.Int).Div(value, weiPerVNT))
+		if err != nil {
+			return err
+		}
+		data = input
+	} else if value.Sign() > 0 {
+		return errors.New("election contract is not payable except $stake")
+	}
+	_, err := new(election.Election).Run(ctx, data)
+	return err
+}
+
+// pendingNonce returns the nonce of the next transaction of the account, including the
+// pending transactions.
+func (n *Node) pendingNonce(addr common.Address) uint64 {
+	nonce := n.state.GetNonce(addr)
+	for found := true; found; {
+		found = false
+		for _, tx := range n.pending {
+			if n.txs[tx.Hash()].from == addr && tx.Nonce() == nonce {
+				nonce++
+				found = true
+			}
+		}
+	}
+	return nonce
+}
+
+// pendingState returns a copy of the latest state with the pending transactions applied.
+func (n *Node) pendingState() *state.StateDB {
+	st := n.state.Copy()
+	for _, tx := range n.pending {
+		from := n.txs[tx.Hash()].from
+		if tx.Nonce() == st.GetNonce(from) {
+			n.applyTx(st, tx, from, n.now)
+		}
+	}
+	return st
+}
+
+func (n *Node) latest() *types.Block {
+	return n.blocks[len(n.blocks)-1]
+}
+
+// intrinsicGas returns the gas used by a transaction of the data, the election contract
+// doesn't require extra gas.
+func intrinsicGas(data []byte) uint64 {
+	gas := params.TxGas
+	for _, b := range data {
+		if b == 0 {
+			gas += params.TxDataZeroGas
+		} else {
+			gas += params.TxDataNonZeroGas
+		}
+	}
+	return gas
+}
+
+// chainContext is the context of running election contract.
+type chainContext struct {
+	state  inter.StateDB
+	origin common.Address
+	time   *big.Int
+}
+
+func (c *chainContext) GetStateDb() inter.StateDB { return c.state }
+func (c *chainContext) GetOrigin() common.Address { return c.origin }
+func (c *chainContext) GetTime() *big.Int         { return c.time }
@@ -0,0 +1,61 @@
+package electtest
+
+import (
+	"context"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vntchain/go-vnt/accounts"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/vntclient"
+)
+
+func TestNodeReplacePending(t *testing.T) {
+	node := NewNode(2)
+	defer node.Close()
+	server := httptest.NewServer(node.Handler())
+	defer server.Close()
+	vc, err := vntclient.Dial(server.URL)
+	if err != nil {
+		t.Fatalf("dial node error: %s", err)
+	}
+
+	from, to := NewAccount(), NewAccount()
+	node.Fund(from.Address, VNT(10))
+	node.SetAutoMine(false)
+	send := func(gasPrice int64) *types.Transaction {
+		tx := types.NewTransaction(0, to.Address, VNT(1), 21000, big.NewInt(gasPrice), nil)
+		tx, err := from.SignTx(accounts.Account{Address: from.Address}, tx, big.NewInt(2))
+		if err != nil {
+			t.Fatalf("sign error: %s", err)
+		}
+		if err := vc.SendTransaction(context.Background(), tx); err != nil {
+			t.Fatalf("send error: %s", err)
+		}
+		return tx
+	}
+
+	first := send(1)
+	if nonce, _ := vc.PendingNonceAt(context.Background(), from.Address); nonce != 1 {
+		t.Errorf("want pending nonce 1, got: %d", nonce)
+	}
+	second := send(2)
+	if _, _, err := vc.TransactionByHash(context.Background(), first.Hash()); err == nil {
+		t.Errorf("want the replaced transaction dropped, got no error")
+	}
+	if _, pending, err := vc.TransactionByHash(context.Background(), second.Hash()); err != nil || !pending {
+		t.Errorf("want the pending transaction, got: %v, %v", pending, err)
+	}
+
+	block := node.Mine()
+	if len(block.Transactions()) != 1 || block.Transactions()[0].Hash() != second.Hash() {
+		t.Errorf("want the replacement mined, got: %v", block.Transactions())
+	}
+	if balance, _ := vc.BalanceAt(context.Background(), to.Address, nil); balance.Cmp(VNT(1)) != 0 {
+		t.Errorf("want balance 1 VNT, got: %s", balance)
+	}
+	if receipt, err := vc.TransactionReceipt(context.Background(), second.Hash()); err != nil || receipt.Status != types.ReceiptStatusSuccessful {
+		t.Errorf("want successful receipt, got: %v, %v", receipt, err)
+	}
+}
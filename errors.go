@@ -0,0 +1,190 @@
+package elect
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	hubble "github.com/vntchain/go-vnt"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// Errors of the checks before creating transaction. The checks of registering witness
+// return the errors of election contract, such as election.ErrCandiInfoDup.
+var (
+	ErrNoStake             = errors.New("you have no stake")
+	ErrNotVoted            = errors.New("you didn't vote for any witness")
+	ErrNotCandidate        = errors.New("account is not a witness candidate")
+	ErrAlreadyProxy        = errors.New("you are a vote proxy")
+	ErrNotProxy            = errors.New("account is not a vote proxy")
+	ErrHasProxy            = errors.New("you have a vote proxy")
+	ErrNoProxy             = errors.New("you have no proxy")
+	ErrProxySelf           = errors.New("can not set self as your proxy")
+	ErrTooManyCandidates   = errors.New("vote too many witnesses, at most 30")
+	ErrInsufficientBalance = errors.New("balance is not enough")
+	ErrStakeTooSmall       = errors.New("stake is less than 1 VNT")
+	ErrStakeNotWhole       = errors.New("stake must be whole VNT")
+	ErrBountyNotEnough     = errors.New("the rest of bounty is not enough 1000 VNT")
+
+	// ErrWatchOnly is returned if signing transactions by a watch-only Election.
+	ErrWatchOnly = errors.New("election is watch-only, it can not sign transactions")
+
+	// ErrCooldown matches all the cooldown errors, such as ErrVoteCooldown.
+	ErrCooldown = errors.New("cannot do the operation twice within 24 hours")
+)
+
+// ErrVoteCooldown is returned if voting or setting proxy within 24 hours after the last one.
+type ErrVoteCooldown struct {
+	NextAllowed time.Time     // the time of block when it's allowed
+	Remaining   time.Duration // the duration from the latest block to NextAllowed
+}
+
+func (e *ErrVoteCooldown) Error() string {
+	return fmt.Sprintf("cannot vote or set proxy twice within 24 hours, next allowed at %s", cooldownTime(e.NextAllowed, e.Remaining))
+}
+
+// Is makes errors.Is(err, ErrCooldown) true.
+func (e *ErrVoteCooldown) Is(target error) bool { return target == ErrCooldown }
+
+// ErrUnstakeCooldown is returned if unstaking within 24 hours after the last staking.
+type ErrUnstakeCooldown struct {
+	NextAllowed time.Time     // the time of block when it's allowed
+	Remaining   time.Duration // the duration from the latest block to NextAllowed
+}
+
+func (e *ErrUnstakeCooldown) Error() string {
+	return fmt.Sprintf("cannot unstake in 24 hours after staking, next allowed at %s", cooldownTime(e.NextAllowed, e.Remaining))
+}
+
+// Is makes errors.Is(err, ErrCooldown) true.
+func (e *ErrUnstakeCooldown) Is(target error) bool { return target == ErrCooldown }
+
+// ErrExtractCooldown is returned if extracting bounty within 24 hours after the last one.
+type ErrExtractCooldown struct {
+	NextAllowed time.Time     // the time of block when it's allowed
+	Remaining   time.Duration // the duration from the latest block to NextAllowed
+}
+
+func (e *ErrExtractCooldown) Error() string {
+	return fmt.Sprintf("cannot extract bounty twice within 24 hours, next allowed at %s", cooldownTime(e.NextAllowed, e.Remaining))
+}
+
+// Is makes errors.Is(err, ErrCooldown) true.
+func (e *ErrExtractCooldown) Is(target error) bool { return target == ErrCooldown }
+
+// cooldownTime formats the time when the operation is allowed and the remaining duration.
+func cooldownTime(next time.Time, remaining time.Duration) string {
+	return fmt.Sprintf("%s (in %s)", next.Format(time.RFC3339), remaining.Round(time.Second))
+}
+
+// ErrRPC is returned if requesting the node failed, Cause is the error of the request.
+type ErrRPC struct {
+	Cause error
+}
+
+func (e *ErrRPC) Error() string {
+	return fmt.Sprintf("rpc error: %s", e.Cause)
+}
+
+func (e *ErrRPC) Unwrap() error { return e.Cause }
+
+// ErrContract is returned if the node reports the transaction would fail with the error
+// of election contract, Reason is the matched error, such as ErrNoStake or election.ErrCandiInfoDup,
+// or nil if the error is unknown, and Message is the original error reported by node.
+type ErrContract struct {
+	Reason  error
+	Message string
+}
+
+func (e *ErrContract) Error() string {
+	return e.Message
+}
+
+func (e *ErrContract) Unwrap() error { return e.Reason }
+
+// contractErrors maps the errors of election contract to errors, the patterns match the
+// whole messages of the contract, the addresses and numbers in them excepted.
+var contractErrors = []struct {
+	re  *regexp.Regexp
+	err error
+}{
+	{literal(vntelection.ErrCandiNameLenInvalid.Error()), vntelection.ErrCandiNameLenInvalid},
+	{literal(vntelection.ErrCandiUrlLenInvalid.Error()), vntelection.ErrCandiUrlLenInvalid},
+	{literal(vntelection.ErrCandiNameInvalid.Error()), vntelection.ErrCandiNameInvalid},
+	{literal(vntelection.ErrCandiInfoDup.Error()), vntelection.ErrCandiInfoDup},
+	{literal(vntelection.ErrCandiAlreadyRegistered.Error()), vntelection.ErrCandiAlreadyRegistered},
+	{literal("unregisterWitness unregister unknown witness."), ErrNotCandidate},
+	{literal("unregisterWitness witness already inactive."), ErrNotCandidate},
+	{literal("extractOwnBounty unknown witness."), ErrNotCandidate},
+	{literal("you must stake before vote"), ErrNoStake},
+	{literal("unStake stake is not found in db."), ErrNoStake},
+	{literal("unStake 0 stakeCount."), ErrNoStake},
+	{literal("stake not enough balance."), ErrInsufficientBalance},
+	{regexp.MustCompile(`you voted too many candidates: the limit is \d+, you voted \d+`), ErrTooManyCandidates},
+	{literal("startProxy proxy is already started"), ErrAlreadyProxy},
+	{literal("account registered as a proxy is not allowed to use a proxy"), ErrAlreadyProxy},
+	{literal("account that uses a proxy is not allowed to become a proxy"), ErrHasProxy},
+	{regexp.MustCompile(`must cancel proxy first, proxy: [0-9a-f]{40}`), ErrHasProxy},
+	{literal("stopProxy proxy does not exist."), ErrNotProxy},
+	{literal("stopProxy address is not proxy"), ErrNotProxy},
+	{regexp.MustCompile(`[0-9a-f]{40} is not a proxy`), ErrNotProxy},
+	{literal("cannot proxy to self"), ErrProxySelf},
+	{literal("not set proxy"), ErrNoProxy},
+	{regexp.MustCompile(`the rest of bounty \d+ wei is not enough 1000 vnt`), ErrBountyNotEnough},
+	{literal("cannot unstake in 24 hours"), ErrCooldown},
+	{regexp.MustCompile(`it's less than 24h after your last (extract bounty|vote or setProxy)`), ErrCooldown},
+}
+
+// literal returns the regexp matching the message literally.
+func literal(msg string) *regexp.Regexp {
+	return regexp.MustCompile(regexp.QuoteMeta(msg))
+}
+
+// contractReason returns the error of election contract matching the message, or nil.
+func contractReason(msg string) error {
+	for _, c := range contractErrors {
+		if c.re.MatchString(msg) {
+			return c.err
+		}
+	}
+	return nil
+}
+
+// nodeError returns ErrContract if the error returned by node matches an error of election
+// contract, or ErrRPC otherwise.
+func nodeError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if reason := contractReason(err.Error()); reason != nil {
+		return &ErrContract{Reason: reason, Message: err.Error()}
+	}
+	return &ErrRPC{Cause: err}
+}
+
+// contractError returns ErrContract if node reports an error of calling or estimating a
+// transaction of election contract, Reason is nil if it's not a known error of the contract.
+// ErrRPC is returned if the request failed, such as the connection is broken.
+func contractError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(rpc.Error); !ok {
+		return nodeError(err)
+	}
+	return &ErrContract{Reason: contractReason(err.Error()), Message: err.Error()}
+}
+
+// isNonceTooLow returns true if the node rejects the transaction as its nonce is used.
+func isNonceTooLow(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "nonce too low")
+}
+
+// isNotFound returns true if the node returns that the stake, voter, candidates or
+// transaction is not found.
+func isNotFound(err error) bool {
+	return err != nil && err.Error() == hubble.NotFound.Error()
+}
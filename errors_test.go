@@ -0,0 +1,90 @@
+package elect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+)
+
+func TestNodeError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want error
+	}{
+		{vntelection.ErrCandiInfoDup.Error(), vntelection.ErrCandiInfoDup},
+		{"you must stake before vote", ErrNoStake},
+		{"startProxy proxy is already started", ErrAlreadyProxy},
+		{"stopProxy address is not proxy", ErrNotProxy},
+		{"122369f04f32269598789998de33e3d56e2c507a is not a proxy", ErrNotProxy},
+		{"the rest of bounty 5000 wei is not enough 1000 vnt", ErrBountyNotEnough},
+		{"it's less than 24h after your last vote or setProxy, lastTime: 1, now: 2", ErrCooldown},
+	}
+	for _, test := range tests {
+		err := nodeError(errors.New(test.msg))
+		var contractErr *ErrContract
+		if !errors.As(err, &contractErr) || !errors.Is(err, test.want) {
+			t.Errorf("node error %q want: %v, got: %v", test.msg, test.want, err)
+		}
+		if err.Error() != test.msg {
+			t.Errorf("node error message want: %s, got: %s", test.msg, err)
+		}
+	}
+
+	var rpcErr *ErrRPC
+	cause := errors.New("connection refused")
+	if err := nodeError(cause); !errors.As(err, &rpcErr) || rpcErr.Cause != cause {
+		t.Errorf("node error want ErrRPC, got: %v", err)
+	}
+	if nodeError(nil) != nil {
+		t.Errorf("node error of nil want nil")
+	}
+
+	// the messages only similar to the errors of contract are not matched
+	for _, msg := range []string{"stopProxy failed", "the candidate is not a proxy"} {
+		if err := nodeError(errors.New(msg)); !errors.As(err, &rpcErr) {
+			t.Errorf("node error %q want ErrRPC, got: %v", msg, err)
+		}
+	}
+}
+
+// codeError is an error reported by node.
+type codeError string
+
+func (e codeError) Error() string  { return string(e) }
+func (e codeError) ErrorCode() int { return -32000 }
+
+func TestContractError(t *testing.T) {
+	var contractErr *ErrContract
+	msg := "gas required exceeds allowance or always failing transaction"
+	if err := contractError(codeError(msg)); !errors.As(err, &contractErr) || contractErr.Reason != nil || err.Error() != msg {
+		t.Errorf("unknown contract error want ErrContract without reason, got: %#v", err)
+	}
+	if err := contractError(codeError("cannot proxy to self")); !errors.Is(err, ErrProxySelf) {
+		t.Errorf("contract error want ErrProxySelf, got: %v", err)
+	}
+
+	var rpcErr *ErrRPC
+	if err := contractError(errors.New("connection refused")); !errors.As(err, &rpcErr) {
+		t.Errorf("request error want ErrRPC, got: %v", err)
+	}
+}
+
+func TestCooldownError(t *testing.T) {
+	next := time.Unix(1546272000, 0)
+	err := fmt.Errorf("vote: %w", &ErrVoteCooldown{NextAllowed: next, Remaining: 90*time.Minute + 400*time.Millisecond})
+
+	var cooldown *ErrVoteCooldown
+	if !errors.As(err, &cooldown) || !cooldown.NextAllowed.Equal(next) {
+		t.Errorf("want ErrVoteCooldown with next allowed time %s, got: %v", next, err)
+	}
+	if !errors.Is(err, ErrCooldown) {
+		t.Errorf("want errors.Is ErrCooldown, got: %v", err)
+	}
+	if want := "next allowed at " + next.Format(time.RFC3339) + " (in 1h30m0s)"; !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("want error ends with %q, got: %s", want, err)
+	}
+}
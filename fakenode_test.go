@@ -0,0 +1,144 @@
+package elect
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/rlp"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+var testSender = common.HexToAddress("0x122369f04f32269598789998de33e3d56e2c507a")
+
+// FakeCore is the fake core RPC service of a node, it serves the receipts of the
+// transactions mined by mine. It's exported to be registered to rpc.Server.
+type FakeCore struct {
+	mu       sync.Mutex
+	receipts map[common.Hash]map[string]interface{}
+	balance  *big.Int
+	gasPrice *big.Int
+	nonce    uint64
+	callErr  error                // error of calling contract, nil if succeeded
+	sent     []*types.Transaction // transactions received by SendRawTransaction
+}
+
+func newFakeCore() *FakeCore {
+	return &FakeCore{
+		receipts: make(map[common.Hash]map[string]interface{}),
+		balance:  new(big.Int).Mul(big.NewInt(100), big.NewInt(1e18)),
+		gasPrice: big.NewInt(10e+9),
+	}
+}
+
+// mine records the receipt of the transaction mined in the block.
+func (c *FakeCore) mine(hash common.Hash, number uint64, success bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := types.ReceiptStatusFailed
+	if success {
+		status = types.ReceiptStatusSuccessful
+	}
+	c.receipts[hash] = map[string]interface{}{
+		"blockNumber":       hexutil.Uint64(number),
+		"transactionHash":   hash,
+		"gasUsed":           hexutil.Uint64(21000),
+		"cumulativeGasUsed": hexutil.Uint64(21000),
+		"logs":              []*types.Log{},
+		"logsBloom":         types.Bloom{},
+		"status":            hexutil.Uint(status),
+	}
+}
+
+// GetTransactionReceipt returns the receipt of the mined transaction, or nil if not mined.
+func (c *FakeCore) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.receipts[hash], nil
+}
+
+// GetBalance returns the balance of testSender.
+func (c *FakeCore) GetBalance(ctx context.Context, account common.Address, block string) (*hexutil.Big, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return (*hexutil.Big)(c.balance), nil
+}
+
+// GetTransactionCount returns the nonce of testSender.
+func (c *FakeCore) GetTransactionCount(ctx context.Context, account common.Address, block string) (hexutil.Uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return hexutil.Uint64(c.nonce), nil
+}
+
+// GasPrice returns the suggested gas price.
+func (c *FakeCore) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	return (*hexutil.Big)(c.gasPrice), nil
+}
+
+// Call returns callErr if it's set.
+func (c *FakeCore) Call(ctx context.Context, args map[string]interface{}, block string) (hexutil.Bytes, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return hexutil.Bytes{}, c.callErr
+}
+
+// EstimateGas returns the intrinsic gas of a transaction, or callErr if it's set.
+func (c *FakeCore) EstimateGas(ctx context.Context, args map[string]interface{}) (hexutil.Uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return 21000, c.callErr
+}
+
+// SendRawTransaction records the sent transaction.
+func (c *FakeCore) SendRawTransaction(ctx context.Context, data hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		return common.Hash{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, tx)
+	return tx.Hash(), nil
+}
+
+// GetTransactionByHash returns the sent transaction, with the block number if it's mined.
+func (c *FakeCore) GetTransactionByHash(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, tx := range c.sent {
+		if tx.Hash() != hash {
+			continue
+		}
+		data, err := json.Marshal(tx)
+		if err != nil {
+			return nil, err
+		}
+		var ret map[string]interface{}
+		if err := json.Unmarshal(data, &ret); err != nil {
+			return nil, err
+		}
+		if receipt, ok := c.receipts[hash]; ok {
+			ret["blockNumber"] = receipt["blockNumber"]
+		}
+		return ret, nil
+	}
+	return nil, nil
+}
+
+// newTestElection returns an Election of testSender connected to the fake node.
+func newTestElection(t *testing.T, core *FakeCore) *Election {
+	server := rpc.NewServer()
+	if err := server.RegisterName("core", core); err != nil {
+		t.Fatalf("register fake core service error: %s", err)
+	}
+	e := newElection("")
+	e.cfg = &Config{Sender: testSender, ChainID: 2}
+	e.vc = newRpcClient(NewBackend(rpc.DialInProc(server)), 0)
+	return e
+}
@@ -0,0 +1,135 @@
+package elect
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	hubble "github.com/vntchain/go-vnt"
+	"github.com/vntchain/go-vnt/core/types"
+)
+
+// Modes of deciding gas price and gas limit.
+const (
+	GasModeFixed    = "fixed"    // use the fixed gas price or gas limit
+	GasModeSuggest  = "suggest"  // use the gas price suggested by node
+	GasModeEstimate = "estimate" // use the gas limit estimated by node
+)
+
+// defaultGasPrice is the gas price used by fixed mode if price is not set, 18 Gwei.
+var defaultGasPrice = big.NewInt(18000000000)
+
+// GasConfig is the strategy of deciding gas price and gas limit of transactions.
+//
+// Gas price supports fixed and suggest mode, gas limit supports fixed and estimate mode,
+// both default to fixed mode.
+type GasConfig struct {
+	PriceMode  string   `json:"priceMode"`
+	Price      *big.Int `json:"price"`      // fixed mode: gas price in wei, default 18 Gwei
+	Multiplier float64  `json:"multiplier"` // suggest mode: gas price = suggested price * multiplier, default 1
+	MaxPrice   *big.Int `json:"maxPrice"`   // suggest mode: the cap of gas price in wei, no cap if not set
+
+	LimitMode string  `json:"limitMode"`
+	Limit     uint64  `json:"limit"`  // fixed mode: gas limit, default is the gas limit of each operation
+	Margin    float64 `json:"margin"` // estimate mode: gas limit = estimated gas * (1 + margin)
+
+	PriceBump uint64 `json:"priceBump"` // percent of gas price increased to replace a pending transaction, default 10
+
+	Reserve *Amount `json:"reserve"` // balance kept for gas when staking all, default 0.1 VNT
+}
+
+// SetGasPrice sets a fixed gas price in wei for the transactions.
+func (e *Election) SetGasPrice(price *big.Int) {
+	e.cfg.Gas.PriceMode = GasModeFixed
+	e.cfg.Gas.Price = price
+}
+
+// SetGasLimit sets a fixed gas limit for the transactions.
+func (e *Election) SetGasLimit(limit uint64) {
+	e.cfg.Gas.LimitMode = GasModeFixed
+	e.cfg.Gas.Limit = limit
+}
+
+// newElectionTx returns an unsigned transaction of calling election contract, the gas
+// price and gas limit are decided by the gas strategy, defaultLimit is the gas limit
+// of the operation used by fixed mode.
+func (e *Election) newElectionTx(ctx context.Context, value *big.Int, defaultLimit uint64, funcName string, args ...interface{}) (*types.Transaction, error) {
+	gasPrice, err := e.gasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	gasLimit := defaultLimit
+	if e.cfg.Gas.Limit > 0 {
+		gasLimit = e.cfg.Gas.Limit
+	}
+	tx, err := e.vc.NewElectionTx(ctx, e.cfg.Sender, value, gasLimit, gasPrice, funcName, args...)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+
+	switch e.cfg.Gas.LimitMode {
+	case "", GasModeFixed:
+	case GasModeEstimate:
+		if gasLimit, err = e.estimateGasLimit(ctx, tx); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown gas limit mode: %s", e.cfg.Gas.LimitMode)
+	}
+
+	// NewElectionTx uses the latest mined nonce, replace it with the nonce allocated by Election
+	nonce, err := e.allocNonce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	e.log.Debug("Create election transaction", "method", funcName, "nonce", nonce, "gasLimit", gasLimit, "gasPrice", tx.GasPrice())
+	return types.NewTransaction(nonce, *tx.To(), tx.Value(), gasLimit, tx.GasPrice(), tx.Data()), nil
+}
+
+// gasPrice returns the gas price decided by the gas strategy.
+func (e *Election) gasPrice(ctx context.Context) (*big.Int, error) {
+	g := &e.cfg.Gas
+	switch g.PriceMode {
+	case "", GasModeFixed:
+		if g.Price != nil {
+			return g.Price, nil
+		}
+		return defaultGasPrice, nil
+
+	case GasModeSuggest:
+		price, err := e.vc.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("query suggested gas price error: %w", nodeError(err))
+		}
+		if g.Multiplier > 0 {
+			price, _ = new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(g.Multiplier)).Int(nil)
+		}
+		if g.MaxPrice != nil && price.Cmp(g.MaxPrice) > 0 {
+			price = g.MaxPrice
+		}
+		return price, nil
+
+	default:
+		return nil, fmt.Errorf("unknown gas price mode: %s", g.PriceMode)
+	}
+}
+
+// estimateGasLimit returns the gas estimated by node with safety margin.
+func (e *Election) estimateGasLimit(ctx context.Context, tx *types.Transaction) (uint64, error) {
+	msg := hubble.CallMsg{
+		From:     e.cfg.Sender,
+		To:       tx.To(),
+		GasPrice: tx.GasPrice(),
+		Value:    tx.Value(),
+		Data:     tx.Data(),
+	}
+	gas, err := e.vc.EstimateGas(ctx, msg)
+	if err != nil {
+		return 0, fmt.Errorf("estimate gas error: %w", contractError(err))
+	}
+	if e.cfg.Gas.Margin > 0 {
+		gas += uint64(float64(gas) * e.cfg.Gas.Margin)
+	}
+	return gas, nil
+}
@@ -0,0 +1,60 @@
+package elect
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestGasStrategy(t *testing.T) {
+	core := newFakeCore()
+
+	// the fake node suggests 10 Gwei and estimates 21000 gas
+	tests := []struct {
+		name      string
+		gas       GasConfig
+		price     *big.Int // override by SetGasPrice
+		limit     uint64   // override by SetGasLimit
+		wantPrice *big.Int
+		wantLimit uint64
+	}{
+		{name: "fixed default", wantPrice: big.NewInt(18e+9), wantLimit: 30000},
+		{name: "fixed", gas: GasConfig{PriceMode: GasModeFixed, Price: big.NewInt(20e+9), LimitMode: GasModeFixed, Limit: 50000},
+			wantPrice: big.NewInt(20e+9), wantLimit: 50000},
+		{name: "suggest", gas: GasConfig{PriceMode: GasModeSuggest, Multiplier: 1.5},
+			wantPrice: big.NewInt(15e+9), wantLimit: 30000},
+		{name: "suggest capped", gas: GasConfig{PriceMode: GasModeSuggest, Multiplier: 1.5, MaxPrice: big.NewInt(12e+9)},
+			wantPrice: big.NewInt(12e+9), wantLimit: 30000},
+		{name: "estimate", gas: GasConfig{LimitMode: GasModeEstimate, Margin: 0.5},
+			wantPrice: big.NewInt(18e+9), wantLimit: 31500},
+		{name: "override", gas: GasConfig{PriceMode: GasModeSuggest, LimitMode: GasModeEstimate},
+			price: big.NewInt(25e+9), limit: 40000, wantPrice: big.NewInt(25e+9), wantLimit: 40000},
+	}
+
+	for _, test := range tests {
+		e := newTestElection(t, core)
+		e.cfg.Gas = test.gas
+		if test.price != nil {
+			e.SetGasPrice(test.price)
+		}
+		if test.limit > 0 {
+			e.SetGasLimit(test.limit)
+		}
+
+		utx, err := e.BuildUnsignedTx(func() (common.Hash, error) { return e.Stake("1") })
+		if err != nil {
+			t.Errorf("%s: stake error: %s", test.name, err)
+			continue
+		}
+		if utx.GasPrice.Cmp(test.wantPrice) != 0 || utx.GasLimit != test.wantLimit {
+			t.Errorf("%s: want gas price %s and gas limit %d, got: %s, %d", test.name, test.wantPrice, test.wantLimit, utx.GasPrice, utx.GasLimit)
+		}
+	}
+
+	e := newTestElection(t, core)
+	e.cfg.Gas = GasConfig{PriceMode: "unknown"}
+	if _, err := e.BuildUnsignedTx(func() (common.Hash, error) { return e.Stake("1") }); err == nil {
+		t.Errorf("want error of unknown gas price mode, got nil")
+	}
+}
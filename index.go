@@ -0,0 +1,424 @@
+package elect
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	hubble "github.com/vntchain/go-vnt"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+	"github.com/vntchain/go-vnt/vntdb"
+)
+
+// indexReorgDepth is the number of the latest indexed blocks whose hashes are kept for
+// detecting reorgs, a deeper reorg requires indexing again.
+const indexReorgDepth = 128
+
+// Keys of the index database.
+var (
+	indexHeadKey    = []byte("head")    // number and hash of the last indexed block
+	indexGenesisKey = []byte("genesis") // hash of the genesis block of the chain
+	indexHashPrefix = []byte("b")       // b + number -> hash of the indexed block
+	indexTxPrefix   = []byte("t")       // t + number + index -> json of IndexedTx
+)
+
+// ErrReorgTooDeep is returned if the chain is reorganized deeper than the blocks kept by
+// the index, the index should be removed and built again.
+var ErrReorgTooDeep = fmt.Errorf("chain is reorganized deeper than %d blocks", indexReorgDepth)
+
+// IndexedTx is a transaction calling the election contract, with the input decoded against
+// the abi of the contract and the result of its receipt.
+type IndexedTx struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Time        time.Time      `json:"time"`
+	Index       uint           `json:"index"` // index of the transaction in block
+	Hash        common.Hash    `json:"hash"`
+	From        common.Address `json:"from"`
+	Value       *big.Int       `json:"value"`
+	Method      string         `json:"method"` // empty if the input can't be decoded
+	Args        []string       `json:"args"`
+	Input       hexutil.Bytes  `json:"input"`
+	Success     bool           `json:"success"`
+	GasUsed     uint64         `json:"gasUsed"`
+}
+
+// TxFilter selects the indexed transactions, the zero value selects all.
+type TxFilter struct {
+	FromBlock uint64
+	ToBlock   uint64          // 0 means the last indexed block
+	From      *common.Address // sender of the transaction
+	Methods   []string
+}
+
+// SyncResult is the result of syncing the index.
+type SyncResult struct {
+	From     uint64 `json:"from"`     // the first block indexed
+	To       uint64 `json:"to"`       // the last block indexed
+	Blocks   uint64 `json:"blocks"`   // number of blocks indexed
+	Txs      int    `json:"txs"`      // number of election transactions indexed
+	Reverted uint64 `json:"reverted"` // number of blocks reverted by reorgs
+}
+
+// Index is a local database of the transactions calling the election contract. It's
+// built by scanning blocks from the node incrementally, and checks the hashes of the
+// latest indexed blocks to revert the blocks reorganized.
+type Index struct {
+	e  *Election
+	db *vntdb.LDBDatabase
+}
+
+// rpcHeader is the fields of the RPC block used by the index.
+type rpcHeader struct {
+	Number     *hexutil.Big `json:"number"`
+	Hash       common.Hash  `json:"hash"`
+	ParentHash common.Hash  `json:"parentHash"`
+	Timestamp  *hexutil.Big `json:"timestamp"`
+}
+
+// rpcBlock is the RPC block with the full transactions.
+type rpcBlock struct {
+	rpcHeader
+	Transactions []struct {
+		Hash             common.Hash     `json:"hash"`
+		From             common.Address  `json:"from"`
+		To               *common.Address `json:"to"`
+		Input            hexutil.Bytes   `json:"input"`
+		Value            *hexutil.Big    `json:"value"`
+		TransactionIndex hexutil.Uint    `json:"transactionIndex"`
+	} `json:"transactions"`
+}
+
+// OpenIndex opens the index database in dir, or IndexDir of config if dir is empty, which
+// default is ~/.elect/index/<chainID>. It returns an error if opening failed.
+func (e *Election) OpenIndex(dir string) (*Index, error) {
+	if dir == "" {
+		dir = e.cfg.IndexDir
+	}
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("index directory is not set: %s", err)
+		}
+		dir = filepath.Join(home, ".elect", "index", fmt.Sprint(e.cfg.ChainID))
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create index directory error: %s", err)
+	}
+	db, err := vntdb.NewLDBDatabase(dir, 16, 16)
+	if err != nil {
+		return nil, fmt.Errorf("open index database %s error: %s", dir, err)
+	}
+	return &Index{e: e, db: db}, nil
+}
+
+// Close closes the index database.
+func (ix *Index) Close() {
+	ix.db.Close()
+}
+
+// Head returns the number and hash of the last indexed block, ok is false if nothing
+// is indexed.
+func (ix *Index) Head() (number uint64, hash common.Hash, ok bool) {
+	data, err := ix.db.Get(indexHeadKey)
+	if err != nil || len(data) != 8+common.HashLength {
+		return 0, common.Hash{}, false
+	}
+	return binary.BigEndian.Uint64(data[:8]), common.BytesToHash(data[8:]), true
+}
+
+// Sync indexes the blocks after the last indexed block to the latest block, or from the
+// block of number from if nothing is indexed. The reorganized blocks are reverted first.
+// progress is called after each block is indexed if it's not nil.
+func (ix *Index) Sync(ctx context.Context, from uint64, progress func(number, latest uint64)) (*SyncResult, error) {
+	if err := ix.checkGenesis(ctx); err != nil {
+		return nil, err
+	}
+	latest, err := ix.header(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &SyncResult{}
+	next := from
+	if head, _, ok := ix.Head(); ok {
+		// 上次回退时可能没有删除完
+		if err := ix.deleteAfter(head); err != nil {
+			return nil, err
+		}
+		fork, err := ix.findFork(ctx, head)
+		if err != nil {
+			return nil, err
+		}
+		if err := ix.revert(fork); err != nil {
+			return nil, err
+		}
+		ret.Reverted = head - fork
+		next = fork + 1
+	}
+	ret.From = next
+
+	for n := next; n <= latest.Number.ToInt().Uint64(); n++ {
+		if err := ctx.Err(); err != nil {
+			return ret, err
+		}
+		b, err := ix.block(ctx, n)
+		if err != nil {
+			return ret, err
+		}
+
+		// 索引过程中发生了分叉，回退后重新索引
+		if n > 0 {
+			if parent, ok := ix.hash(n - 1); ok && parent != b.ParentHash {
+				fork, err := ix.findFork(ctx, n-1)
+				if err != nil {
+					return ret, err
+				}
+				if err := ix.revert(fork); err != nil {
+					return ret, err
+				}
+				reverted := n - 1 - fork
+				ret.Reverted += reverted
+				if reverted > ret.Blocks {
+					reverted = ret.Blocks
+				}
+				ret.Blocks -= reverted
+				n = fork
+				continue
+			}
+		}
+
+		txs, err := ix.indexBlock(ctx, b)
+		if err != nil {
+			return ret, err
+		}
+		ret.To = n
+		ret.Blocks++
+		ret.Txs += txs
+		if progress != nil {
+			progress(n, latest.Number.ToInt().Uint64())
+		}
+	}
+	return ret, nil
+}
+
+// Txs returns the indexed transactions selected by the filter in the order of blocks.
+func (ix *Index) Txs(f *TxFilter) ([]*IndexedTx, error) {
+	if f == nil {
+		f = &TxFilter{}
+	}
+	methods := make(map[string]bool)
+	for _, m := range f.Methods {
+		methods[m] = true
+	}
+
+	it := ix.db.NewIteratorWithPrefix(indexTxPrefix)
+	defer it.Release()
+	var ret []*IndexedTx
+	for ok := it.Seek(txKey(f.FromBlock, 0)); ok; ok = it.Next() {
+		var tx IndexedTx
+		if err := json.Unmarshal(it.Value(), &tx); err != nil {
+			return nil, fmt.Errorf("decode indexed transaction error: %s", err)
+		}
+		if f.ToBlock > 0 && tx.BlockNumber > f.ToBlock {
+			break
+		}
+		if f.From != nil && tx.From != *f.From || len(methods) > 0 && !methods[tx.Method] {
+			continue
+		}
+		ret = append(ret, &tx)
+	}
+	return ret, it.Error()
+}
+
+// checkGenesis makes sure the index is built from the chain of the node.
+func (ix *Index) checkGenesis(ctx context.Context) error {
+	genesis, err := ix.header(ctx, big.NewInt(0))
+	if err != nil {
+		return err
+	}
+	stored, err := ix.db.Get(indexGenesisKey)
+	if err != nil {
+		return ix.db.Put(indexGenesisKey, genesis.Hash.Bytes())
+	}
+	if !bytes.Equal(stored, genesis.Hash.Bytes()) {
+		return fmt.Errorf("index is built from another chain, genesis: %s, node genesis: %s", common.BytesToHash(stored).String(), genesis.Hash.String())
+	}
+	return nil
+}
+
+// findFork returns the last block not reorganized, which is the head if no reorg.
+func (ix *Index) findFork(ctx context.Context, head uint64) (uint64, error) {
+	for n := head; ; n-- {
+		stored, ok := ix.hash(n)
+		if !ok || head-n >= indexReorgDepth {
+			return 0, ErrReorgTooDeep
+		}
+		h, err := ix.header(ctx, new(big.Int).SetUint64(n))
+		if err != nil && !errors.Is(err, hubble.NotFound) {
+			return 0, err
+		}
+		if err == nil && h.Hash == stored {
+			return n, nil
+		}
+		if n == 0 {
+			return 0, ErrReorgTooDeep
+		}
+	}
+}
+
+// revert removes the indexed blocks after the block of number fork.
+func (ix *Index) revert(fork uint64) error {
+	head, _, ok := ix.Head()
+	if !ok || head <= fork {
+		return nil
+	}
+	hash, _ := ix.hash(fork)
+	if err := ix.db.Put(indexHeadKey, headValue(fork, hash)); err != nil {
+		return err
+	}
+	if err := ix.deleteAfter(fork); err != nil {
+		return err
+	}
+	ix.e.log.Info("Reverted reorganized blocks", "from", fork+1, "to", head)
+	return nil
+}
+
+// deleteAfter deletes the transactions and hashes of the blocks after the block of number.
+func (ix *Index) deleteAfter(number uint64) error {
+	var keys [][]byte
+	for _, start := range [][]byte{txKey(number+1, 0), hashKey(number + 1)} {
+		it := ix.db.NewIteratorWithPrefix(start[:1])
+		for ok := it.Seek(start); ok; ok = it.Next() {
+			keys = append(keys, common.CopyBytes(it.Key()))
+		}
+		it.Release()
+		if err := it.Error(); err != nil {
+			return err
+		}
+	}
+	for _, k := range keys {
+		if err := ix.db.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexBlock writes the election transactions of block, the hash of block and the head
+// in a batch, and returns the number of the transactions.
+func (ix *Index) indexBlock(ctx context.Context, b *rpcBlock) (int, error) {
+	number := b.Number.ToInt().Uint64()
+	contract := common.HexToAddress(vntelection.ContractAddr)
+	batch := ix.db.NewBatch()
+	count := 0
+	for _, t := range b.Transactions {
+		if t.To == nil || *t.To != contract {
+			continue
+		}
+		ret, err := ix.e.transactionResult(ctx, t.Hash)
+		if err != nil {
+			return 0, fmt.Errorf("get receipt of transaction %s error: %w", t.Hash.String(), err)
+		}
+		tx := &IndexedTx{
+			BlockNumber: number,
+			BlockHash:   b.Hash,
+			Time:        time.Unix(b.Timestamp.ToInt().Int64(), 0),
+			Index:       uint(t.TransactionIndex),
+			Hash:        t.Hash,
+			From:        t.From,
+			Value:       hexBig(t.Value),
+			Input:       t.Input,
+			Success:     ret.Success,
+			GasUsed:     ret.GasUsed,
+		}
+		if method, args, err := decodeInput(t.Input); err == nil {
+			tx.Method, tx.Args = method, args
+		}
+		data, err := json.Marshal(tx)
+		if err != nil {
+			return 0, err
+		}
+		batch.Put(txKey(number, tx.Index), data)
+		count++
+	}
+	batch.Put(hashKey(number), b.Hash.Bytes())
+	batch.Put(indexHeadKey, headValue(number, b.Hash))
+	if err := batch.Write(); err != nil {
+		return 0, fmt.Errorf("write index of block %d error: %s", number, err)
+	}
+	if number >= indexReorgDepth {
+		ix.db.Delete(hashKey(number - indexReorgDepth))
+	}
+	return count, nil
+}
+
+// hash returns the hash of the indexed block of number.
+func (ix *Index) hash(number uint64) (common.Hash, bool) {
+	data, err := ix.db.Get(hashKey(number))
+	if err != nil {
+		return common.Hash{}, false
+	}
+	return common.BytesToHash(data), true
+}
+
+// header returns the header of block of number, or the latest block if number is nil.
+func (ix *Index) header(ctx context.Context, number *big.Int) (*rpcHeader, error) {
+	var h *rpcHeader
+	if err := ix.e.vc.CallContext(ctx, &h, "core_getBlockByNumber", blockNumberArg(number), false); err != nil {
+		return nil, nodeError(err)
+	}
+	if h == nil || h.Number == nil {
+		return nil, hubble.NotFound
+	}
+	return h, nil
+}
+
+// block returns the block of number with the full transactions.
+func (ix *Index) block(ctx context.Context, number uint64) (*rpcBlock, error) {
+	var b *rpcBlock
+	if err := ix.e.vc.CallContext(ctx, &b, "core_getBlockByNumber", hexutil.EncodeUint64(number), true); err != nil {
+		return nil, nodeError(err)
+	}
+	if b == nil || b.Number == nil {
+		return nil, fmt.Errorf("block %d: %w", number, hubble.NotFound)
+	}
+	return b, nil
+}
+
+func blockNumberArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return hexutil.EncodeBig(number)
+}
+
+func hashKey(number uint64) []byte {
+	key := make([]byte, len(indexHashPrefix)+8)
+	copy(key, indexHashPrefix)
+	binary.BigEndian.PutUint64(key[len(indexHashPrefix):], number)
+	return key
+}
+
+func txKey(number uint64, index uint) []byte {
+	key := make([]byte, len(indexTxPrefix)+12)
+	copy(key, indexTxPrefix)
+	binary.BigEndian.PutUint64(key[len(indexTxPrefix):], number)
+	binary.BigEndian.PutUint32(key[len(indexTxPrefix)+8:], uint32(index))
+	return key
+}
+
+func headValue(number uint64, hash common.Hash) []byte {
+	value := make([]byte, 8, 8+common.HashLength)
+	binary.BigEndian.PutUint64(value, number)
+	return append(value, hash.Bytes()...)
+}
@@ -0,0 +1,146 @@
+package elect_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vntchain/elect"
+	"github.com/vntchain/elect/electtest"
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestIndexReorg(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	acc := electtest.NewAccount()
+	node.Fund(acc.Address, electtest.VNT(100))
+	e := testElection(t, node, acc, dir)
+	mustSucceed(t, e, "stake", func() (common.Hash, error) { return e.Stake("10") })
+	mustSucceed(t, e, "start proxy", e.StartProxy)
+
+	ix, err := e.OpenIndex(dir + "/index")
+	if err != nil {
+		t.Fatalf("open index error: %s", err)
+	}
+	defer ix.Close()
+	sync := func() *elect.SyncResult {
+		t.Helper()
+		ret, err := ix.Sync(context.Background(), 0, nil)
+		if err != nil {
+			t.Fatalf("sync index error: %s", err)
+		}
+		return ret
+	}
+	methods := func() []string {
+		t.Helper()
+		txs, err := ix.Txs(nil)
+		if err != nil {
+			t.Fatalf("list indexed transactions error: %s", err)
+		}
+		var ret []string
+		for _, tx := range txs {
+			ret = append(ret, tx.Method)
+		}
+		return ret
+	}
+
+	if ret := sync(); ret.Txs != 2 || ret.To != node.BlockNumber() {
+		t.Errorf("want 2 transactions indexed to block %d, got: %+v", node.BlockNumber(), ret)
+	}
+	if m := methods(); len(m) != 2 || m[0] != "$stake" || m[1] != "startProxy" {
+		t.Errorf("want stake and startProxy indexed, got: %v", m)
+	}
+	if ret := sync(); ret.Blocks != 0 || ret.Reverted != 0 {
+		t.Errorf("want nothing indexed again, got: %+v", ret)
+	}
+
+	// the block of startProxy is replaced by a block of stake, the nonce cache ahead of
+	// the chain expires after 10 minutes
+	head := node.BlockNumber()
+	if err := node.Rewind(head - 1); err != nil {
+		t.Fatalf("rewind error: %s", err)
+	}
+	node.AdvanceTime(11 * time.Minute)
+	mustSucceed(t, e, "stake", func() (common.Hash, error) { return e.Stake("5") })
+	if ret := sync(); ret.Reverted != 1 || ret.Txs != 1 || ret.To != head {
+		t.Errorf("want 1 block reverted and 1 transaction indexed, got: %+v", ret)
+	}
+	if m := methods(); len(m) != 2 || m[1] != "$stake" {
+		t.Errorf("want startProxy replaced by stake, got: %v", m)
+	}
+	txs, err := ix.Txs(&elect.TxFilter{FromBlock: head, From: &acc.Address})
+	if err != nil || len(txs) != 1 || !txs[0].Success || txs[0].Value.Cmp(electtest.VNT(5)) != 0 {
+		t.Errorf("want the stake of 5 VNT in block %d, got: %v, %v", head, txs, err)
+	}
+}
+
+func TestIndexVoters(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	witnessAcc, voterAcc, proxyAcc, delegatorAcc := voteWitness(t, node, dir)
+
+	ix, err := testElection(t, node, voterAcc, dir).OpenIndex(dir + "/index")
+	if err != nil {
+		t.Fatalf("open index error: %s", err)
+	}
+	defer ix.Close()
+	if _, err := ix.Sync(context.Background(), 0, nil); err != nil {
+		t.Fatalf("sync index error: %s", err)
+	}
+	cv, err := ix.Voters(context.Background(), witnessAcc.Address)
+	if err != nil {
+		t.Fatalf("query voters error: %s", err)
+	}
+	if cv.Name != "node1" || cv.TotalVotes.Sign() <= 0 || cv.TotalVotes.Cmp(cv.VoteCount) != 0 {
+		t.Errorf("want the votes of voters equal to the candidate votes %s, got: %s", cv.VoteCount, cv.TotalVotes)
+	}
+	if len(cv.Voters) != 3 {
+		t.Fatalf("want 3 voters, got: %d", len(cv.Voters))
+	}
+	p, v, d := cv.Voters[0], cv.Voters[1], cv.Voters[2]
+	if p.Address != proxyAcc.Address || v.Address != voterAcc.Address || d.Address != delegatorAcc.Address {
+		t.Errorf("want proxy, voter and delegator in order, got: %s, %s, %s", p.Address.String(), v.Address.String(), d.Address.String())
+	}
+	if p.Proxy != nil || d.Proxy == nil || *d.Proxy != proxyAcc.Address || p.ProxyVoteCount.Cmp(d.Votes) != 0 {
+		t.Errorf("want the delegator votes %s delegated to the proxy, got: %s", d.Votes, p.ProxyVoteCount)
+	}
+}
+
+// voteWitness registers a witness, which is voted by a voter and a proxy, and a delegator
+// sets the proxy. It returns the accounts of them.
+func voteWitness(t *testing.T, node *electtest.Node, dir string) (witnessAcc, voterAcc, proxyAcc, delegatorAcc *electtest.Account) {
+	t.Helper()
+	witnessAcc, voterAcc, proxyAcc, delegatorAcc = electtest.NewAccount(), electtest.NewAccount(), electtest.NewAccount(), electtest.NewAccount()
+	for _, a := range []*electtest.Account{witnessAcc, voterAcc, proxyAcc, delegatorAcc} {
+		node.Fund(a.Address, electtest.VNT(100))
+	}
+	witness, voterE := testElection(t, node, witnessAcc, dir), testElection(t, node, voterAcc, dir)
+	proxy, delegator := testElection(t, node, proxyAcc, dir), testElection(t, node, delegatorAcc, dir)
+	mustSucceed(t, witness, "register", func() (common.Hash, error) {
+		return witness.RegisterWitness("node1", testNodeUrl, "www.node1.com")
+	})
+	vote := func(e *elect.Election) (common.Hash, error) { return e.Vote([]string{witnessAcc.Address.String()}) }
+	mustSucceed(t, voterE, "stake", func() (common.Hash, error) { return voterE.Stake("10") })
+	mustSucceed(t, voterE, "vote", func() (common.Hash, error) { return vote(voterE) })
+	mustSucceed(t, proxy, "start proxy", proxy.StartProxy)
+	mustSucceed(t, proxy, "stake", func() (common.Hash, error) { return proxy.Stake("20") })
+	mustSucceed(t, proxy, "vote", func() (common.Hash, error) { return vote(proxy) })
+	mustSucceed(t, delegator, "stake", func() (common.Hash, error) { return delegator.Stake("30") })
+	mustSucceed(t, delegator, "set proxy", func() (common.Hash, error) { return delegator.SetProxy(proxyAcc.Address.String()) })
+	return witnessAcc, voterAcc, proxyAcc, delegatorAcc
+}
@@ -0,0 +1,29 @@
+//go:build !windows
+// +build !windows
+
+package elect
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile returns the opened file after it's exclusively locked, blocks until the lock
+// is acquired.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// unlockFile unlocks and closes the file locked by lockFile.
+func unlockFile(f *os.File) error {
+	defer f.Close()
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
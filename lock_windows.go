@@ -0,0 +1,44 @@
+//go:build windows
+// +build windows
+
+package elect
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modkernel32      = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = modkernel32.NewProc("LockFileEx")
+	procUnlockFileEx = modkernel32.NewProc("UnlockFileEx")
+)
+
+// lockfileExclusiveLock is LOCKFILE_EXCLUSIVE_LOCK of LockFileEx.
+const lockfileExclusiveLock = 0x2
+
+// lockFile returns the opened file after it's exclusively locked, blocks until the lock
+// is acquired. The lock is released by the system if the process exits.
+func lockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, err
+	}
+	ol := new(syscall.Overlapped)
+	if r, _, err := procLockFileEx.Call(f.Fd(), lockfileExclusiveLock, 0, 1, 0, uintptr(unsafe.Pointer(ol))); r == 0 {
+		f.Close()
+		return nil, err
+	}
+	return f, nil
+}
+
+// unlockFile unlocks and closes the file locked by lockFile.
+func unlockFile(f *os.File) error {
+	defer f.Close()
+	ol := new(syscall.Overlapped)
+	if r, _, err := procUnlockFileEx.Call(f.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(ol))); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package elect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// nonceCacheTTL is the duration that the cached nonce is trusted when it's ahead of
+// the pending nonce of node. After that, the transactions are considered dropped.
+var nonceCacheTTL = 10 * time.Minute
+
+// nonceCache is the nonce cache of an account on a chain, it's shared by
+// Elections of different processes.
+type nonceCache struct {
+	Nonce     uint64 `json:"nonce"`     // nonce of the next transaction
+	UpdatedAt int64  `json:"updatedAt"` // unix timestamp of the last sent transaction
+}
+
+// nonceFile returns the path of nonce cache file of the account and chain, or an
+// empty string if there is no directory for the cache.
+func (e *Election) nonceFile() string {
+	dir := e.cfg.NonceDir
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".elect", "nonce")
+	}
+	return filepath.Join(dir, fmt.Sprintf("%d_%s.json", e.cfg.ChainID, strings.ToLower(e.cfg.Sender.Hex())))
+}
+
+// allocNonce returns the nonce of the next transaction, and locks the nonce cache
+// until the transaction is sent or failed.
+func (e *Election) allocNonce(ctx context.Context) (uint64, error) {
+	path := e.nonceFile()
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			return 0, fmt.Errorf("create nonce cache directory error: %s", err)
+		}
+		lock, err := lockFile(path + ".lock")
+		if err != nil {
+			return 0, fmt.Errorf("lock nonce cache error: %s", err)
+		}
+		e.nonceLock = lock
+	}
+
+	pending, err := e.vc.PendingNonceAt(ctx, e.cfg.Sender)
+	if err != nil {
+		e.releaseNonce()
+		return 0, fmt.Errorf("query pending nonce of account: %s error: %w", e.cfg.Sender.String(), nodeError(err))
+	}
+	if path == "" {
+		return pending, nil
+	}
+
+	cache, err := readNonceCache(path)
+	if err != nil {
+		e.releaseNonce()
+		return 0, err
+	}
+	return reconcileNonce(cache, pending, e.now()), nil
+}
+
+// commitNonce saves the nonce after nonce of the sent transaction to cache.
+func (e *Election) commitNonce(nonce uint64) {
+	if e.nonceLock == nil {
+		return
+	}
+
+	// 交易已经发送成功，缓存写入失败时下次会使用链上的nonce
+	path := e.nonceFile()
+	data, err := json.Marshal(&nonceCache{Nonce: nonce + 1, UpdatedAt: e.now().Unix()})
+	if err == nil {
+		err = ioutil.WriteFile(path, data, 0600)
+	}
+	if err != nil {
+		e.log.Warn("Write nonce cache failed", "path", path, "err", err)
+	}
+}
+
+// releaseNonce unlocks the nonce cache if it's locked.
+func (e *Election) releaseNonce() {
+	if e.nonceLock == nil {
+		return
+	}
+	unlockFile(e.nonceLock)
+	e.nonceLock = nil
+}
+
+// readNonceCache returns the nonce cache in file, or nil if the file not exists.
+func readNonceCache(path string) (*nonceCache, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read nonce cache error: %s", err)
+	}
+
+	cache := &nonceCache{}
+	if err := json.Unmarshal(data, cache); err != nil {
+		// 缓存损坏时以链上的nonce为准
+		return nil, nil
+	}
+	return cache, nil
+}
+
+// reconcileNonce returns the nonce of the next transaction decided by the cache and
+// the pending nonce of node.
+func reconcileNonce(cache *nonceCache, pending uint64, now time.Time) uint64 {
+	// 缓存落后于链上，说明有从其他途径发送的交易
+	if cache == nil || cache.Nonce <= pending {
+		return pending
+	}
+
+	// 缓存领先于链上，短时间内认为交易还未同步到节点，否则认为交易已被丢弃
+	if now.Sub(time.Unix(cache.UpdatedAt, 0)) < nonceCacheTTL {
+		return cache.Nonce
+	}
+	return pending
+}
@@ -0,0 +1,26 @@
+package elect
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReconcileNonce(t *testing.T) {
+	now := time.Unix(1546272000, 0)
+	tests := []struct {
+		cache   *nonceCache
+		pending uint64
+		want    uint64
+	}{
+		{nil, 5, 5},
+		{&nonceCache{Nonce: 3, UpdatedAt: now.Unix()}, 5, 5},
+		{&nonceCache{Nonce: 7, UpdatedAt: now.Unix() - 60}, 5, 7},
+		{&nonceCache{Nonce: 7, UpdatedAt: now.Add(-nonceCacheTTL).Unix()}, 5, 5},
+	}
+
+	for i, tt := range tests {
+		if got := reconcileNonce(tt.cache, tt.pending, now); got != tt.want {
+			t.Errorf("case %d: nonce want: %d, got: %d", i, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package elect
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/core/types"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+	"github.com/vntchain/go-vnt/rlp"
+)
+
+// Op is an operation of Election which creates a transaction, such as e.Unstake, or
+// a closure like func() (common.Hash, error) { return e.Stake("1") }.
+type Op func() (common.Hash, error)
+
+// UnsignedTx is the json format of an unsigned election transaction. It's built on an
+// online host, signed on an offline host and broadcast later.
+//
+// Method and Args are decoded from Data for displaying, the signer decodes Data again
+// and rejects the transaction if they are not match.
+type UnsignedTx struct {
+	ChainID  int            `json:"chainID"`
+	From     common.Address `json:"from"`
+	To       common.Address `json:"to"`
+	Nonce    uint64         `json:"nonce"`
+	Value    *big.Int       `json:"value"` // in wei
+	GasLimit uint64         `json:"gasLimit"`
+	GasPrice *big.Int       `json:"gasPrice"` // in wei
+	Data     hexutil.Bytes  `json:"data"`     // abi encoded input of election contract
+	Method   string         `json:"method"`
+	Args     []string       `json:"args"`
+}
+
+// SignedTx is the json format of a signed election transaction.
+type SignedTx struct {
+	UnsignedTx
+	Hash common.Hash   `json:"hash"`
+	Raw  hexutil.Bytes `json:"raw"` // rlp encoded signed transaction
+}
+
+// BuildUnsignedTx runs op without signing and sending the transaction, returns the
+// unsigned transaction created by op, or an error if op failed.
+func (e *Election) BuildUnsignedTx(op Op) (*UnsignedTx, error) {
+	unSignTx, err := e.captureTx(op)
+	if err != nil {
+		return nil, err
+	}
+	return e.toUnsignedTx(unSignTx)
+}
+
+// LastSentTx returns the last transaction sent by Election in unsigned format, or nil
+// if no transaction is sent. It's used for displaying the method and args of it.
+func (e *Election) LastSentTx() *UnsignedTx {
+	if e.lastTx == nil {
+		return nil
+	}
+	utx, err := e.toUnsignedTx(e.lastTx)
+	if err != nil {
+		return nil
+	}
+	return utx
+}
+
+// toUnsignedTx returns the unsigned format of the election transaction.
+func (e *Election) toUnsignedTx(tx *types.Transaction) (*UnsignedTx, error) {
+	method, args, err := decodeInput(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedTx{
+		ChainID:  e.cfg.ChainID,
+		From:     e.cfg.Sender,
+		To:       *tx.To(),
+		Nonce:    tx.Nonce(),
+		Value:    tx.Value(),
+		GasLimit: tx.Gas(),
+		GasPrice: tx.GasPrice(),
+		Data:     tx.Data(),
+		Method:   method,
+		Args:     args,
+	}, nil
+}
+
+// captureTx runs op and returns the unsigned transaction created by op, the
+// transaction is not signed and sent.
+func (e *Election) captureTx(op Op) (*types.Transaction, error) {
+	var unSignTx *types.Transaction
+	e.handleTx = func(tx *types.Transaction) (common.Hash, error) {
+		unSignTx = tx
+		return emptyHash, nil
+	}
+	defer func() { e.handleTx = nil }()
+
+	if _, err := op(); err != nil {
+		return nil, err
+	}
+	if unSignTx == nil {
+		return nil, errors.New("no transaction is created by the operation")
+	}
+	return unSignTx, nil
+}
+
+// Verify returns an error if the unsigned transaction is not a transaction of election
+// contract, or Method and Args are not match with Data.
+func (utx *UnsignedTx) Verify() error {
+	if utx.To != common.HexToAddress(vntelection.ContractAddr) {
+		return fmt.Errorf("transaction is not sent to election contract, to: %s", utx.To.String())
+	}
+	if utx.Value == nil || utx.GasPrice == nil {
+		return errors.New("value and gas price of transaction are required")
+	}
+
+	method, args, err := decodeInput(utx.Data)
+	if err != nil {
+		return err
+	}
+	if method != utx.Method || !reflect.DeepEqual(args, utx.Args) {
+		return fmt.Errorf("method or args doesn't match data, decoded from data: %s(%v)", method, args)
+	}
+	return nil
+}
+
+// Transaction returns the unsigned transaction.
+func (utx *UnsignedTx) Transaction() *types.Transaction {
+	return types.NewTransaction(utx.Nonce, utx.To, utx.Value, utx.GasLimit, utx.GasPrice, utx.Data)
+}
+
+// SignTx verifies and signs the unsigned transaction with the account of config, it
+// doesn't need any connection to node.
+func (e *Election) SignTx(utx *UnsignedTx) (*SignedTx, error) {
+	if utx.From != e.cfg.Sender {
+		return nil, fmt.Errorf("transaction is from %s, not the account of config: %s", utx.From.String(), e.cfg.Sender.String())
+	}
+	if utx.ChainID != e.cfg.ChainID {
+		return nil, fmt.Errorf("chain id of transaction is %d, not the chain id of config: %d", utx.ChainID, e.cfg.ChainID)
+	}
+	if err := utx.Verify(); err != nil {
+		return nil, err
+	}
+
+	tx, err := e.signTx(utx.Transaction(), utx.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignedTx{UnsignedTx: *utx, Hash: tx.Hash(), Raw: raw}, nil
+}
+
+// decode verifies and decodes the signed transaction. Raw must be the embedded unsigned
+// transaction signed by From on the chain of ChainID, so what is displayed is what is sent.
+func (stx *SignedTx) decode() (*types.Transaction, error) {
+	if err := stx.UnsignedTx.Verify(); err != nil {
+		return nil, err
+	}
+
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(stx.Raw, tx); err != nil {
+		return nil, fmt.Errorf("decode signed transaction error: %s", err)
+	}
+	if tx.Hash() != stx.Hash {
+		return nil, fmt.Errorf("hash of signed transaction is %s, not %s", tx.Hash().String(), stx.Hash.String())
+	}
+
+	// 签名哈希覆盖了to、nonce、value、gas、gas price、data和chain id
+	signer := types.NewEIP155Signer(big.NewInt(int64(stx.ChainID)))
+	if signer.Hash(tx) != signer.Hash(stx.Transaction()) {
+		return nil, errors.New("signed transaction doesn't match the unsigned transaction")
+	}
+	from, err := types.Sender(signer, tx)
+	if err != nil {
+		return nil, fmt.Errorf("recover sender of signed transaction error: %s", err)
+	}
+	if from != stx.From {
+		return nil, fmt.Errorf("transaction is signed by %s, not %s", from.String(), stx.From.String())
+	}
+	return tx, nil
+}
+
+// SendSignedTx verifies and broadcasts the signed transaction, returns the tx hash, or an
+// error if failed.
+func (e *Election) SendSignedTx(stx *SignedTx) (common.Hash, error) {
+	return e.SendSignedTxContext(e.ctx, stx)
+}
+
+// SendSignedTxContext is the same as SendSignedTx, but uses ctx for the RPC calls.
+func (e *Election) SendSignedTxContext(ctx context.Context, stx *SignedTx) (common.Hash, error) {
+	if stx.ChainID != e.cfg.ChainID {
+		return emptyHash, fmt.Errorf("chain id of transaction is %d, not the chain id of config: %d", stx.ChainID, e.cfg.ChainID)
+	}
+	tx, err := stx.decode()
+	if err != nil {
+		return emptyHash, err
+	}
+
+	if err := e.vc.SendTransaction(ctx, tx); err != nil {
+		return emptyHash, nodeError(err)
+	}
+	e.lastTx = tx
+	return tx.Hash(), nil
+}
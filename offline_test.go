@@ -0,0 +1,87 @@
+package elect
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+	"github.com/vntchain/go-vnt/crypto"
+	"github.com/vntchain/go-vnt/rlp"
+)
+
+func TestUnsignedTxVerify(t *testing.T) {
+	witness := common.HexToAddress("0x122369f04f32269598789998de33e3d56e2c507a")
+	data, err := electionABI.Pack("voteWitnesses", []common.Address{witness})
+	if err != nil {
+		t.Fatalf("pack input error: %s", err)
+	}
+
+	utx := &UnsignedTx{
+		To:       common.HexToAddress(vntelection.ContractAddr),
+		Value:    big.NewInt(0),
+		GasLimit: 60000,
+		GasPrice: big.NewInt(18000000000),
+		Data:     data,
+		Method:   "voteWitnesses",
+		Args:     []string{witness.String()},
+	}
+	if err := utx.Verify(); err != nil {
+		t.Errorf("want no error, got: %s", err)
+	}
+
+	utx.Args = []string{common.HexToAddress("0x3dcf0b3787c31b2bdf62d5bc9128a79c2bb18829").String()}
+	if err := utx.Verify(); err == nil {
+		t.Errorf("want error of args not match data, got nil")
+	}
+}
+
+func TestSignedTxDecode(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	data, err := electionABI.Pack("unStake")
+	if err != nil {
+		t.Fatalf("pack input error: %s", err)
+	}
+	utx := UnsignedTx{
+		ChainID:  2,
+		From:     crypto.PubkeyToAddress(key.PublicKey),
+		To:       common.HexToAddress(vntelection.ContractAddr),
+		Nonce:    3,
+		Value:    big.NewInt(0),
+		GasLimit: 60000,
+		GasPrice: big.NewInt(18000000000),
+		Data:     data,
+		Method:   "unStake",
+		Args:     []string{},
+	}
+	sign := func(utx UnsignedTx, chainID int64) *SignedTx {
+		tx, err := types.SignTx(utx.Transaction(), types.NewEIP155Signer(big.NewInt(chainID)), key)
+		if err != nil {
+			t.Fatalf("sign tx error: %s", err)
+		}
+		raw, _ := rlp.EncodeToBytes(tx)
+		return &SignedTx{UnsignedTx: utx, Hash: tx.Hash(), Raw: raw}
+	}
+
+	if _, err := sign(utx, 2).decode(); err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+
+	tampered := utx
+	tampered.Nonce = 4
+	tests := []struct {
+		name string
+		stx  *SignedTx
+	}{
+		{"raw differs from unsigned", func() *SignedTx { stx := sign(tampered, 2); stx.UnsignedTx = utx; return stx }()},
+		{"other chain", sign(utx, 1)},
+		{"other signer", func() *SignedTx { stx := sign(utx, 2); stx.From = testSender; return stx }()},
+		{"wrong hash", func() *SignedTx { stx := sign(utx, 2); stx.Hash = common.Hash{1}; return stx }()},
+	}
+	for _, test := range tests {
+		if _, err := test.stx.decode(); err == nil {
+			t.Errorf("%s: want error, got nil", test.name)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package elect
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/vntchain/go-vnt/accounts"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/log"
+)
+
+// Signer signs the transactions of account, it covers the methods of accounts.Wallet
+// used by elect, so a keystore wallet can be used as a Signer.
+type Signer interface {
+	// SignTx signs the transaction by the unlocked account, it returns keystore.ErrLocked
+	// if the account is locked, then the transaction is signed by SignTxWithPassphrase.
+	SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// Option configures the Election created by NewElectionWithConfig.
+type Option func(*Election)
+
+// WithClient makes Election use b to request the node instead of dialing RpcUrl of config.
+// The RPC timeout of config is still applied to each call of b.
+func WithClient(b Backend) Option {
+	return func(e *Election) {
+		e.backend = b
+	}
+}
+
+// WithSigner makes Election sign transactions by s instead of the wallet in the keystore
+// directory of config. The password of account is only requested if s returns keystore.ErrLocked.
+func WithSigner(s Signer) Option {
+	return func(e *Election) {
+		e.signer = s
+	}
+}
+
+// WithClock makes Election use now as the current time, which is used by the nonce cache.
+// The cooldown checks use the time of the latest block instead.
+func WithClock(now func() time.Time) Option {
+	return func(e *Election) {
+		e.now = now
+	}
+}
+
+// WithLogger makes Election write debug logs of creating, signing and sending transactions
+// to l. By default only the warnings are written to stderr.
+func WithLogger(l log.Logger) Option {
+	return func(e *Election) {
+		e.log = l
+	}
+}
+
+// newElection returns a Election with the default clock, logger and context, then applies opts.
+func newElection(cfgPath string, opts ...Option) *Election {
+	logger := log.New()
+	logger.SetHandler(log.LvlFilterHandler(log.LvlWarn, log.StreamHandler(os.Stderr, log.LogfmtFormat())))
+	e := &Election{
+		cfgPath: cfgPath,
+		ctx:     context.Background(),
+		now:     time.Now,
+		log:     logger,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
@@ -0,0 +1,94 @@
+package elect
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/vntchain/go-vnt/accounts"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// fakeBackend implements the methods of Backend used by unstaking, the others panic.
+type fakeBackend struct {
+	Backend
+	stake *rpc.Stake
+	now   func() time.Time // time of the latest block
+	sent  []*types.Transaction
+}
+
+func (b *fakeBackend) StakeAt(ctx context.Context, account common.Address) (*rpc.Stake, error) {
+	return b.stake, nil
+}
+
+func (b *fakeBackend) NewElectionTx(ctx context.Context, sender common.Address, value *big.Int, gasLimit uint64, gasPrice *big.Int, funcName string, args ...interface{}) (*types.Transaction, error) {
+	return types.NewTransaction(0, common.HexToAddress("0x09"), value, gasLimit, gasPrice, []byte(funcName)), nil
+}
+
+func (b *fakeBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return &types.Header{Number: big.NewInt(1), Time: big.NewInt(b.now().Unix())}, nil
+}
+
+func (b *fakeBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return uint64(len(b.sent)), nil
+}
+
+func (b *fakeBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+// fakeSigner returns the transaction without signing.
+type fakeSigner struct{}
+
+func (fakeSigner) SignTx(account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return tx, nil
+}
+
+func (fakeSigner) SignTxWithPassphrase(account accounts.Account, passphrase string, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return nil, errors.New("should not request password")
+}
+
+func TestElectionWithOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	staked := time.Unix(1500000000, 0)
+	now := staked.Add(time.Hour)
+	backend := &fakeBackend{
+		stake: &rpc.Stake{StakeCount: big.NewInt(1), LastStakeTimeStamp: big.NewInt(staked.Unix())},
+		now:   func() time.Time { return now },
+	}
+	cfg := &Config{
+		Sender:   common.HexToAddress("0x122369f04f32269598789998de33e3d56e2c507a"),
+		ChainID:  1234,
+		NonceDir: dir,
+	}
+	e, err := NewElectionWithConfig(cfg, WithClient(backend), WithSigner(fakeSigner{}), WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+
+	var cooldown *ErrUnstakeCooldown
+	if _, err := e.Unstake(); !errors.As(err, &cooldown) {
+		t.Fatalf("want ErrUnstakeCooldown, got: %v", err)
+	}
+
+	now = staked.Add(25 * time.Hour)
+	hash, err := e.Unstake()
+	if err != nil {
+		t.Fatalf("want no error, got: %s", err)
+	}
+	if len(backend.sent) != 1 || backend.sent[0].Hash() != hash {
+		t.Errorf("want the transaction sent by backend, got: %v", backend.sent)
+	}
+}
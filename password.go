@@ -0,0 +1,138 @@
+package elect
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/console"
+)
+
+// Prefixes of password source.
+const (
+	passwordPrompt  = "prompt"
+	passwordEnvPre  = "env:"
+	passwordFilePre = "file:"
+	passwordCmdPre  = "cmd:"
+)
+
+// PasswordProvider provides the password of an account for signing transactions.
+type PasswordProvider interface {
+	Password(account common.Address) (string, error)
+}
+
+// NewPasswordProvider returns the PasswordProvider of the password source, or an
+// error if the source is invalid. Supported sources:
+//
+//	prompt                   prompt for password on terminal without echo
+//	env:ELECT_PASSWORD       read password from environment variable
+//	file:/path/to/password   read password from file, which can only be accessed by owner
+//	cmd:pass show vnt/elect  run external command and use its output as password
+func NewPasswordProvider(source string) (PasswordProvider, error) {
+	switch {
+	case source == passwordPrompt:
+		return promptPassword{}, nil
+	case strings.HasPrefix(source, passwordEnvPre):
+		return envPassword(strings.TrimPrefix(source, passwordEnvPre)), nil
+	case strings.HasPrefix(source, passwordFilePre):
+		return filePassword(strings.TrimPrefix(source, passwordFilePre)), nil
+	case strings.HasPrefix(source, passwordCmdPre):
+		args := strings.Fields(strings.TrimPrefix(source, passwordCmdPre))
+		if len(args) == 0 {
+			return nil, errors.New("password command is empty")
+		}
+		return cmdPassword(args), nil
+	default:
+		return nil, fmt.Errorf("unknown password source: %s", source)
+	}
+}
+
+// staticPassword is the plaintext password in config.
+type staticPassword string
+
+func (p staticPassword) Password(account common.Address) (string, error) {
+	return string(p), nil
+}
+
+// promptPassword prompts for password on terminal.
+type promptPassword struct{}
+
+func (promptPassword) Password(account common.Address) (string, error) {
+	return console.Stdin.PromptPassword(fmt.Sprintf("Password of account %s: ", account.String()))
+}
+
+// envPassword reads password from the environment variable.
+type envPassword string
+
+func (p envPassword) Password(account common.Address) (string, error) {
+	pwd, ok := os.LookupEnv(string(p))
+	if !ok {
+		return "", fmt.Errorf("environment variable %s of password is not set", string(p))
+	}
+	return pwd, nil
+}
+
+// filePassword reads password from the file.
+type filePassword string
+
+func (p filePassword) Password(account common.Address) (string, error) {
+	if err := checkPrivateFile(string(p)); err != nil {
+		return "", err
+	}
+	data, err := ioutil.ReadFile(string(p))
+	if err != nil {
+		return "", fmt.Errorf("read password file error: %s", err)
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// cmdPassword runs the external command and uses its output as password.
+type cmdPassword []string
+
+func (p cmdPassword) Password(account common.Address) (string, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command(p[0], p[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("run password command %s error: %s, %s", p[0], err, strings.TrimSpace(stderr.String()))
+	}
+	// 类似pass的工具，第一行为密码
+	return strings.SplitN(strings.TrimRight(string(out), "\r\n"), "\n", 2)[0], nil
+}
+
+// checkPrivateFile returns an error if the file can be accessed by group or others.
+func checkPrivateFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0077 != 0 {
+		return fmt.Errorf("permissions %#o of %s are too open, it should be accessed by owner only", info.Mode().Perm(), path)
+	}
+	return nil
+}
+
+// passwordProvider returns the PasswordProvider of the current account.
+func (e *Election) passwordProvider() (PasswordProvider, error) {
+	if e.password != nil {
+		return e.password, nil
+	}
+	if e.cfg.PasswordSource != "" {
+		return NewPasswordProvider(e.cfg.PasswordSource)
+	}
+	return staticPassword(e.cfg.Password), nil
+}
+
+// SetPasswordProvider sets the PasswordProvider of accounts, which takes precedence
+// over the password and password source in config.
+func (e *Election) SetPasswordProvider(p PasswordProvider) {
+	e.password = p
+}
@@ -0,0 +1,58 @@
+package elect
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestPasswordProvider(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "password")
+	if err := ioutil.WriteFile(path, []byte("secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("ELECT_TEST_PASSWORD", "secret")
+	defer os.Unsetenv("ELECT_TEST_PASSWORD")
+
+	tests := []struct {
+		source  string
+		want    string
+		wantErr bool
+	}{
+		{"env:ELECT_TEST_PASSWORD", "secret", false},
+		{"env:ELECT_TEST_NO_PASSWORD", "", true},
+		{"file:" + path, "", true}, // readable by others
+		{"cmd:echo secret", "secret", false},
+		{"unknown", "", true},
+	}
+
+	for _, tt := range tests {
+		var got string
+		p, err := NewPasswordProvider(tt.source)
+		if err == nil {
+			got, err = p.Password(common.Address{})
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("source %s: want error: %v, got: %v", tt.source, tt.wantErr, err)
+		} else if got != tt.want {
+			t.Errorf("source %s: password want: %s, got: %s", tt.source, tt.want, got)
+		}
+	}
+
+	if err := os.Chmod(path, 0600); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := NewPasswordProvider("file:" + path)
+	if got, err := p.Password(common.Address{}); err != nil || got != "secret" {
+		t.Errorf("password file with mode 0600: want secret, got: %s, %v", got, err)
+	}
+}
@@ -0,0 +1,484 @@
+package elect
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/params"
+	"github.com/vntchain/go-vnt/rlp"
+)
+
+// Sources of the bounty shared by a payout.
+const (
+	BountyExtracted   = "extracted"   // the bounty extracted since the last paid period
+	BountyExtractable = "extractable" // the rest of bounty can be extracted
+)
+
+// Status of a payee of payout.
+const (
+	PayeePlanned = "planned" // the transfer is not signed
+	PayeeSkipped = "skipped" // the amount is less than the minimum
+	PayeeSigned  = "signed"  // the transfer is signed and recorded in ledger, but not sent
+	PayeeSent    = "sent"    // the transfer is sent to node
+)
+
+// ErrPayoutPaid is returned if the period of payout is already paid.
+var ErrPayoutPaid = errors.New("the period is already paid")
+
+// payoutSignAttempts is the maximum times of signing the transfers of a payout in a Pay,
+// the transfers are signed again if their nonces are used by other transactions.
+const payoutSignAttempts = 3
+
+// PayoutRequest is the parameters of sharing the bounty of a candidate with its voters.
+type PayoutRequest struct {
+	Period    string         // name of the period, such as 2019-06, each period is paid only once
+	Candidate common.Address // the candidate sharing its bounty
+	Source    string         // BountyExtracted or BountyExtractable, or empty to use Bounty
+	Bounty    *Amount        // the bounty of the period if Source is empty
+	Percent   float64        // percent of the bounty shared with voters, (0, 100]
+	MinAmount *Amount        // the payees of less amount are skipped, nil means no minimum
+	Exclude   []common.Address
+}
+
+// Payout is the bounty shared with the voters of a candidate for a period. It's planned
+// from the current votes, and recorded in the ledger when signed, so the same period is
+// never paid twice.
+type Payout struct {
+	Period          string         `json:"period"`
+	Candidate       common.Address `json:"candidate"`
+	Sender          common.Address `json:"sender"`
+	ChainTime       time.Time      `json:"chainTime"` // time of the latest block when planned
+	IndexedTo       uint64         `json:"indexedTo"` // the last indexed block when planned
+	Source          string         `json:"source"`
+	Bounty          *Amount        `json:"bounty"`
+	ExtractedBounty *big.Int       `json:"extractedBounty"` // total extracted bounty of the candidate when planned
+	Percent         float64        `json:"percent"`
+	Pool            *Amount        `json:"pool"`       // the shared bounty, Bounty * Percent
+	TotalVotes      *big.Int       `json:"totalVotes"` // votes of the payees
+	Paid            *Amount        `json:"paid"`       // sum of the transfers
+	Dust            *Amount        `json:"dust"`       // not paid due to rounding and skipped payees
+	GasPrice        *big.Int       `json:"gasPrice"`
+	GasCost         *Amount        `json:"gasCost"` // gas of the transfers
+	Payees          []*Payee       `json:"payees"`
+}
+
+// Payee is a voter receiving the share of bounty. The votes delegated to a proxy are
+// attributed to the delegators found in the index, and the rest to the proxy.
+type Payee struct {
+	Address common.Address  `json:"address"`
+	Proxy   *common.Address `json:"proxy"` // the proxy voting for the payee
+	Votes   *big.Int        `json:"votes"`
+	Share   float64         `json:"share"` // percent of TotalVotes
+	Amount  *Amount         `json:"amount"`
+	Status  string          `json:"status"`
+	Nonce   uint64          `json:"nonce"`
+	Hash    *common.Hash    `json:"hash"` // nil if not signed
+	RawTx   hexutil.Bytes   `json:"rawTx,omitempty"`
+}
+
+// payoutLedger is the payouts of a candidate on a chain.
+type payoutLedger struct {
+	Payouts []*Payout `json:"payouts"`
+}
+
+// Done returns true if all the transfers of the payout are sent.
+func (p *Payout) Done() bool {
+	for _, payee := range p.Payees {
+		if payee.Status == PayeeSigned || payee.Status == PayeePlanned {
+			return false
+		}
+	}
+	return true
+}
+
+// PlanPayout returns the payout of the period computed from the current votes of the
+// voters found in the index. If the period is recorded in the ledger but not all sent,
+// the recorded payout is returned to continue, or ErrPayoutPaid if all sent.
+func (ix *Index) PlanPayout(ctx context.Context, req *PayoutRequest) (*Payout, error) {
+	if req.Period == "" {
+		return nil, errors.New("period of payout is not set")
+	}
+	if req.Percent <= 0 || req.Percent > 100 {
+		return nil, fmt.Errorf("percent should be in (0, 100], got: %v", req.Percent)
+	}
+	e := ix.e
+	ledger, err := readPayoutLedger(e.payoutFile(req.Candidate))
+	if err != nil {
+		return nil, err
+	}
+	if p := ledger.find(req.Period); p != nil {
+		if p.Done() {
+			return nil, fmt.Errorf("%w: %s", ErrPayoutPaid, req.Period)
+		}
+		return p, nil
+	}
+
+	cv, err := ix.Voters(ctx, req.Candidate)
+	if err != nil {
+		return nil, err
+	}
+	if cv.Name == "" {
+		return nil, ErrNotCandidate
+	}
+	now, err := e.chainTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+	_, c := findCandidate(candidates, req.Candidate)
+	if c == nil {
+		return nil, ErrNotCandidate
+	}
+	p := &Payout{
+		Period:          req.Period,
+		Candidate:       req.Candidate,
+		Sender:          e.cfg.Sender,
+		ChainTime:       now,
+		IndexedTo:       cv.IndexedTo,
+		Source:          req.Source,
+		ExtractedBounty: hexBig(c.ExtractedBounty),
+		Percent:         req.Percent,
+		TotalVotes:      big.NewInt(0),
+	}
+
+	// 激励来源：上期以来提取的激励、可提取的激励或指定的数量
+	switch req.Source {
+	case BountyExtracted:
+		bounty := new(big.Int).Set(p.ExtractedBounty)
+		if last := ledger.last(); last != nil {
+			bounty.Sub(bounty, last.ExtractedBounty)
+		}
+		p.Bounty = NewAmount(bounty)
+	case BountyExtractable:
+		p.Bounty = NewAmount(new(big.Int).Sub(hexBig(c.TotalBounty), p.ExtractedBounty))
+	case "":
+		if req.Bounty == nil {
+			return nil, errors.New("bounty of payout is not set")
+		}
+		p.Bounty = req.Bounty
+	default:
+		return nil, fmt.Errorf("unknown bounty source: %s", req.Source)
+	}
+	if p.Bounty.Wei().Sign() <= 0 {
+		return nil, fmt.Errorf("no bounty to share: %s", p.Bounty)
+	}
+	pool := new(big.Int).Mul(p.Bounty.Wei(), big.NewInt(int64(req.Percent*100+0.5)))
+	p.Pool = NewAmount(pool.Div(pool, big.NewInt(10000)))
+
+	p.Payees = payeesOf(cv, req.Exclude)
+	for _, payee := range p.Payees {
+		p.TotalVotes.Add(p.TotalVotes, payee.Votes)
+	}
+	if p.TotalVotes.Sign() <= 0 {
+		return nil, fmt.Errorf("no voter of candidate %s is found in the index", req.Candidate.String())
+	}
+
+	// 按票数比例分配，向下取整，不足最小金额的跳过
+	paid := big.NewInt(0)
+	for _, payee := range p.Payees {
+		amount := new(big.Int).Mul(pool, payee.Votes)
+		amount.Div(amount, p.TotalVotes)
+		share, _ := new(big.Float).Quo(new(big.Float).SetInt(payee.Votes), new(big.Float).SetInt(p.TotalVotes)).Float64()
+		payee.Share = share * 100
+		payee.Amount = NewAmount(amount)
+		payee.Status = PayeePlanned
+		if amount.Sign() == 0 || req.MinAmount != nil && amount.Cmp(req.MinAmount.Wei()) < 0 {
+			payee.Status = PayeeSkipped
+			continue
+		}
+		paid.Add(paid, amount)
+	}
+	p.Paid = NewAmount(paid)
+	p.Dust = NewAmount(new(big.Int).Sub(pool, paid))
+
+	if p.GasPrice, err = e.gasPrice(ctx); err != nil {
+		return nil, err
+	}
+	gas := new(big.Int).Mul(p.GasPrice, new(big.Int).SetUint64(params.TxGas))
+	p.GasCost = NewAmount(gas.Mul(gas, big.NewInt(int64(p.transfers()))))
+	return p, nil
+}
+
+// Pay signs the transfers of the payout with sequential nonces, records them in the
+// ledger, and then sends them. If the payout is recorded, only the transfers not sent
+// are sent again, so it's safe to pay again after failure. The transfers whose nonces
+// are used by other transactions are signed again with new nonces.
+func (e *Election) Pay(ctx context.Context, p *Payout) error {
+	path := e.payoutFile(p.Candidate)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("create payout ledger directory error: %s", err)
+	}
+	lock, err := lockFile(path + ".lock")
+	if err != nil {
+		return fmt.Errorf("lock payout ledger error: %s", err)
+	}
+	defer unlockFile(lock)
+
+	ledger, err := readPayoutLedger(path)
+	if err != nil {
+		return err
+	}
+	if recorded := ledger.find(p.Period); recorded != nil {
+		if recorded.Done() {
+			return fmt.Errorf("%w: %s", ErrPayoutPaid, p.Period)
+		}
+		p = recorded
+	} else {
+		ledger.Payouts = append(ledger.Payouts, p)
+	}
+
+	for i := 0; i < payoutSignAttempts; i++ {
+		// 记录账本后再发送，中断后重新发送相同的交易不会重复支付
+		if p.unsigned() > 0 {
+			if err := e.signPayout(ctx, p); err != nil {
+				return err
+			}
+			if err := ledger.write(path); err != nil {
+				return err
+			}
+		}
+		if err := e.sendPayout(ctx, p); err != nil {
+			if werr := ledger.write(path); werr != nil {
+				return fmt.Errorf("%w, and %s", err, werr)
+			}
+			return err
+		}
+		if p.unsigned() == 0 {
+			return ledger.write(path)
+		}
+	}
+	if err := ledger.write(path); err != nil {
+		return err
+	}
+	return fmt.Errorf("nonces of %d payout transfers are used by other transactions", p.unsigned())
+}
+
+// sendPayout sends the signed transfers of the payout. The transfer whose nonce is used
+// by another transaction is reset to PayeePlanned to be signed again.
+func (e *Election) sendPayout(ctx context.Context, p *Payout) error {
+	for _, payee := range p.Payees {
+		if payee.Status != PayeeSigned {
+			continue
+		}
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(payee.RawTx, tx); err != nil {
+			return fmt.Errorf("decode signed transfer to %s error: %s", payee.Address.String(), err)
+		}
+		if err := e.vc.SendTransaction(ctx, tx); err != nil && !e.knownTx(ctx, tx.Hash()) {
+			// 节点不知道该交易且nonce已被使用，该转账不会再被打包，需要重新签名
+			if isNonceTooLow(err) {
+				e.log.Info("Nonce of payout transfer is used, signing it again", "to", payee.Address, "hash", tx.Hash(), "nonce", tx.Nonce())
+				payee.Status, payee.Nonce, payee.Hash, payee.RawTx = PayeePlanned, 0, nil, nil
+				continue
+			}
+			return fmt.Errorf("send transfer to %s error: %w", payee.Address.String(), nodeError(err))
+		}
+		e.log.Info("Sent payout transfer", "to", payee.Address, "hash", tx.Hash(), "nonce", tx.Nonce())
+		payee.Status = PayeeSent
+	}
+	return nil
+}
+
+// Payouts returns the recorded payouts of the candidate.
+func (e *Election) Payouts(candidate common.Address) ([]*Payout, error) {
+	ledger, err := readPayoutLedger(e.payoutFile(candidate))
+	if err != nil {
+		return nil, err
+	}
+	return ledger.Payouts, nil
+}
+
+// signPayout signs the planned transfers of the payout with sequential nonces after
+// checking the balance is enough.
+func (e *Election) signPayout(ctx context.Context, p *Payout) error {
+	if p.Sender != e.cfg.Sender {
+		return fmt.Errorf("payout is planned for sender %s, not %s", p.Sender.String(), e.cfg.Sender.String())
+	}
+	balance, err := e.vc.BalanceAt(ctx, e.cfg.Sender, nil)
+	if err != nil {
+		return nodeError(err)
+	}
+	cost := new(big.Int).Mul(p.GasPrice, new(big.Int).SetUint64(params.TxGas))
+	cost.Mul(cost, big.NewInt(int64(p.unsigned())))
+	for _, payee := range p.Payees {
+		if payee.Status == PayeePlanned {
+			cost.Add(cost, payee.Amount.Wei())
+		}
+	}
+	if balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w, need %s, have %s", ErrInsufficientBalance, NewAmount(cost), NewAmount(balance))
+	}
+
+	nonce, err := e.allocNonce(ctx)
+	if err != nil {
+		return err
+	}
+	defer e.releaseNonce()
+	signed := make(map[*Payee]*types.Transaction)
+	for _, payee := range p.Payees {
+		if payee.Status != PayeePlanned {
+			continue
+		}
+		tx, err := e.signTx(types.NewTransaction(nonce, payee.Address, payee.Amount.Wei(), params.TxGas, p.GasPrice, nil), e.cfg.ChainID)
+		if err != nil {
+			return err
+		}
+		signed[payee] = tx
+		nonce++
+	}
+	for payee, tx := range signed {
+		raw, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return err
+		}
+		hash := tx.Hash()
+		payee.Status, payee.Nonce, payee.Hash, payee.RawTx = PayeeSigned, tx.Nonce(), &hash, raw
+	}
+	if len(signed) > 0 {
+		e.commitNonce(nonce - 1)
+	}
+	return nil
+}
+
+// knownTx returns true if the node knows the transaction, it's sent before.
+func (e *Election) knownTx(ctx context.Context, hash common.Hash) bool {
+	_, _, err := e.vc.TransactionByHash(ctx, hash)
+	return err == nil
+}
+
+// unsigned returns the number of transfers not signed.
+func (p *Payout) unsigned() int {
+	n := 0
+	for _, payee := range p.Payees {
+		if payee.Status == PayeePlanned {
+			n++
+		}
+	}
+	return n
+}
+
+// transfers returns the number of transfers to send.
+func (p *Payout) transfers() int {
+	n := 0
+	for _, payee := range p.Payees {
+		if payee.Status != PayeeSkipped {
+			n++
+		}
+	}
+	return n
+}
+
+// payeesOf returns the payees of the voters, the votes of a direct voter are its own
+// votes, and the votes delegated to a proxy but not found in delegators are the proxy's.
+func payeesOf(cv *CandidateVoters, exclude []common.Address) []*Payee {
+	excluded := make(map[common.Address]bool)
+	for _, addr := range exclude {
+		excluded[addr] = true
+	}
+	delegated := make(map[common.Address]*big.Int)
+	for _, v := range cv.Voters {
+		if v.Proxy != nil {
+			if delegated[*v.Proxy] == nil {
+				delegated[*v.Proxy] = big.NewInt(0)
+			}
+			delegated[*v.Proxy].Add(delegated[*v.Proxy], v.Votes)
+		}
+	}
+
+	var payees []*Payee
+	for _, v := range cv.Voters {
+		votes := new(big.Int).Set(v.Votes)
+		if v.Proxy == nil {
+			votes.Set(v.LastVoteCount)
+			if rest := new(big.Int).Sub(v.ProxyVoteCount, nonNil(delegated[v.Address])); rest.Sign() > 0 {
+				votes.Add(votes, rest)
+			}
+		}
+		if excluded[v.Address] || votes.Sign() <= 0 {
+			continue
+		}
+		payees = append(payees, &Payee{Address: v.Address, Proxy: v.Proxy, Votes: votes})
+	}
+	return payees
+}
+
+func nonNil(n *big.Int) *big.Int {
+	if n == nil {
+		return common.Big0
+	}
+	return n
+}
+
+// payoutFile returns the path of the payout ledger of the candidate on the chain.
+func (e *Election) payoutFile(candidate common.Address) string {
+	dir := e.cfg.PayoutDir
+	if dir == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".elect", "payout")
+		}
+	}
+	return filepath.Join(dir, fmt.Sprintf("%d_%s.ledger.json", e.cfg.ChainID, strings.ToLower(candidate.Hex())))
+}
+
+// readPayoutLedger returns the ledger in file, or an empty ledger if the file not exists.
+func readPayoutLedger(path string) (*payoutLedger, error) {
+	ledger := &payoutLedger{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ledger, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("read payout ledger error: %s", err)
+	}
+	// 账本损坏时不能以空账本继续，否则可能重复支付
+	if err := json.Unmarshal(data, ledger); err != nil {
+		return nil, fmt.Errorf("decode payout ledger %s error: %s", path, err)
+	}
+	return ledger, nil
+}
+
+// write writes the ledger to a temporary file and renames it to path.
+func (l *payoutLedger) write(path string) error {
+	data, err := json.MarshalIndent(l, "", "    ")
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path+".tmp", data, 0600); err != nil {
+		return fmt.Errorf("write payout ledger error: %s", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("write payout ledger error: %s", err)
+	}
+	return nil
+}
+
+// find returns the payout of the period, or nil if not found.
+func (l *payoutLedger) find(period string) *Payout {
+	for _, p := range l.Payouts {
+		if p.Period == period {
+			return p
+		}
+	}
+	return nil
+}
+
+// last returns the last recorded payout, or nil if no payout.
+func (l *payoutLedger) last() *Payout {
+	if len(l.Payouts) == 0 {
+		return nil
+	}
+	return l.Payouts[len(l.Payouts)-1]
+}
@@ -0,0 +1,183 @@
+package elect_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/vntchain/elect"
+	"github.com/vntchain/elect/electtest"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/params"
+)
+
+func TestPayout(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	witnessAcc, voterAcc, proxyAcc, delegatorAcc := voteWitness(t, node, dir)
+	witness := testElection(t, node, witnessAcc, dir)
+	if err := node.GrantBounty(witnessAcc.Address, electtest.VNT(2000)); err != nil {
+		t.Fatalf("grant bounty error: %s", err)
+	}
+	mustSucceed(t, witness, "extract bounty", witness.ExtractBounty)
+
+	ix, err := witness.OpenIndex(dir + "/index")
+	if err != nil {
+		t.Fatalf("open index error: %s", err)
+	}
+	defer ix.Close()
+	if _, err := ix.Sync(context.Background(), 0, nil); err != nil {
+		t.Fatalf("sync index error: %s", err)
+	}
+	req := &elect.PayoutRequest{Period: "2019-06", Candidate: witnessAcc.Address, Source: elect.BountyExtracted, Percent: 50}
+	p, err := ix.PlanPayout(context.Background(), req)
+	if err != nil {
+		t.Fatalf("plan payout error: %s", err)
+	}
+	if p.Bounty.Wei().Cmp(electtest.VNT(2000)) != 0 || p.Pool.Wei().Cmp(electtest.VNT(1000)) != 0 {
+		t.Errorf("want bounty 2000 VNT and pool 1000 VNT, got: %s, %s", p.Bounty, p.Pool)
+	}
+	if len(p.Payees) != 3 {
+		t.Fatalf("want 3 payees, got: %d", len(p.Payees))
+	}
+	amounts := make(map[string]*big.Int)
+	sum := new(big.Int).Set(p.Dust.Wei())
+	for _, payee := range p.Payees {
+		want := new(big.Int).Mul(p.Pool.Wei(), payee.Votes)
+		if want.Div(want, p.TotalVotes); payee.Amount.Wei().Cmp(want) != 0 {
+			t.Errorf("want %s paid to %s, got: %s", want, payee.Address.String(), payee.Amount.Wei())
+		}
+		amounts[payee.Address.String()] = payee.Amount.Wei()
+		sum.Add(sum, payee.Amount.Wei())
+	}
+	if sum.Cmp(p.Pool.Wei()) != 0 {
+		t.Errorf("want the payouts and dust sum to the pool %s, got: %s", p.Pool.Wei(), sum)
+	}
+
+	before := map[string]*big.Int{}
+	for _, a := range []*electtest.Account{voterAcc, proxyAcc, delegatorAcc} {
+		before[a.Address.String()] = node.Balance(a.Address)
+	}
+	if err := witness.Pay(context.Background(), p); err != nil {
+		t.Fatalf("pay error: %s", err)
+	}
+	for _, a := range []*electtest.Account{voterAcc, proxyAcc, delegatorAcc} {
+		gain := new(big.Int).Sub(node.Balance(a.Address), before[a.Address.String()])
+		if gain.Cmp(amounts[a.Address.String()]) != 0 {
+			t.Errorf("want %s paid to %s, got: %s", amounts[a.Address.String()], a.Address.String(), gain)
+		}
+	}
+
+	// the period is paid only once
+	if err := witness.Pay(context.Background(), p); !errors.Is(err, elect.ErrPayoutPaid) {
+		t.Errorf("pay twice want ErrPayoutPaid, got: %v", err)
+	}
+	if _, err := ix.PlanPayout(context.Background(), req); !errors.Is(err, elect.ErrPayoutPaid) {
+		t.Errorf("plan the paid period want ErrPayoutPaid, got: %v", err)
+	}
+	req.Period = "2019-07"
+	if _, err := ix.PlanPayout(context.Background(), req); err == nil {
+		t.Errorf("plan without bounty extracted since the last period want error, got nil")
+	}
+	if payouts, err := witness.Payouts(witnessAcc.Address); err != nil || len(payouts) != 1 || !payouts[0].Done() {
+		t.Errorf("want the paid period in ledger, got: %v, %v", payouts, err)
+	}
+}
+
+// failingBackend fails sending transactions if fail is set.
+type failingBackend struct {
+	elect.Backend
+	fail bool
+}
+
+func (b *failingBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if b.fail {
+		return errors.New("connection refused")
+	}
+	return b.Backend.SendTransaction(ctx, tx)
+}
+
+func TestPayoutNonceUsed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "elect")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	node := electtest.NewNode(testChainID)
+	defer node.Close()
+	witnessAcc, _, _, _ := voteWitness(t, node, dir)
+	backend := &failingBackend{Backend: elect.NewBackend(node.Dial())}
+	cfg := &elect.Config{Sender: witnessAcc.Address, ChainID: testChainID, NonceDir: dir, PayoutDir: dir}
+	witness, err := elect.NewElectionWithConfig(cfg, elect.WithClient(backend), elect.WithSigner(witnessAcc), elect.WithClock(node.Now))
+	if err != nil {
+		t.Fatalf("new election error: %s", err)
+	}
+
+	ix, err := witness.OpenIndex(dir + "/index")
+	if err != nil {
+		t.Fatalf("open index error: %s", err)
+	}
+	defer ix.Close()
+	if _, err := ix.Sync(context.Background(), 0, nil); err != nil {
+		t.Fatalf("sync index error: %s", err)
+	}
+	req := &elect.PayoutRequest{Period: "2019-06", Candidate: witnessAcc.Address, Bounty: elect.NewAmount(electtest.VNT(30)), Percent: 100}
+	p, err := ix.PlanPayout(context.Background(), req)
+	if err != nil {
+		t.Fatalf("plan payout error: %s", err)
+	}
+
+	// the transfers are signed and recorded, but sending failed
+	backend.fail = true
+	if err := witness.Pay(context.Background(), p); err == nil {
+		t.Fatalf("pay with sending failed want error, got nil")
+	}
+	payouts, err := witness.Payouts(witnessAcc.Address)
+	if err != nil || len(payouts) != 1 || payouts[0].Payees[0].Status != elect.PayeeSigned {
+		t.Fatalf("want the signed transfers in ledger, got: %v, %v", payouts, err)
+	}
+	first := payouts[0].Payees[0]
+
+	// another transaction uses the nonce of the first transfer
+	tx := types.NewTransaction(first.Nonce, witnessAcc.Address, big.NewInt(0), params.TxGas, electtest.DefaultGasPrice, nil)
+	signed, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(testChainID)), witnessAcc.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := node.SendTransaction(signed); err != nil {
+		t.Fatalf("send transaction of the nonce error: %s", err)
+	}
+
+	before := make(map[common.Address]*big.Int)
+	for _, payee := range p.Payees {
+		before[payee.Address] = node.Balance(payee.Address)
+	}
+	backend.fail = false
+	if err := witness.Pay(context.Background(), p); err != nil {
+		t.Fatalf("pay again error: %s", err)
+	}
+	payouts, err = witness.Payouts(witnessAcc.Address)
+	if err != nil || len(payouts) != 1 || !payouts[0].Done() {
+		t.Fatalf("want the period paid, got: %v, %v", payouts, err)
+	}
+	if resigned := payouts[0].Payees[0]; *resigned.Hash == *first.Hash || resigned.Nonce == first.Nonce {
+		t.Errorf("want the first transfer signed again with a new nonce, got nonce %d", resigned.Nonce)
+	}
+	for _, payee := range payouts[0].Payees {
+		gain := new(big.Int).Sub(node.Balance(payee.Address), before[payee.Address])
+		if gain.Cmp(payee.Amount.Wei()) != 0 {
+			t.Errorf("want %s paid to %s, got: %s", payee.Amount.Wei(), payee.Address.String(), gain)
+		}
+	}
+}
@@ -1,34 +1,80 @@
 package elect
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"math/big"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
 )
 
-var errNotFound = "not found"
+// minExtractBounty is the minimum bounty in wei can be extracted once, 1000 VNT.
+var minExtractBounty = big.NewInt(0).Mul(big.NewInt(1e+18), big.NewInt(1000))
+
+// stakeInfo is the stake information with the amount rendered in VNT and wei.
+type stakeInfo struct {
+	rpc.Stake
+	StakeCount *Amount `json:"stakeCount"`
+}
+
+// candidateInfo is the witness candidate with the bounty rendered in VNT and wei.
+type candidateInfo struct {
+	rpc.Candidate
+	TotalBounty     *Amount `json:"totalBounty"`
+	ExtractedBounty *Amount `json:"extractedBounty"`
+}
 
 // QueryStake returns stake information of the account in json format, or an error if failed.
 func (e *Election) QueryStake() ([]byte, error) {
-	stake, err := e.vc.StakeAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		if err.Error() == errNotFound {
-			return nil, fmt.Errorf("no stake information for account: %s", e.cfg.Sender.String())
-		}
-		return nil, err
+	return e.QueryStakeContext(e.ctx)
+}
+
+// QueryStakeContext is the same as QueryStake, but uses ctx for the RPC calls.
+func (e *Election) QueryStakeContext(ctx context.Context) ([]byte, error) {
+	return e.QueryStakeOfContext(ctx, e.cfg.Sender)
+}
+
+// QueryStakeOf returns stake information of any account in json format, or an error if failed.
+func (e *Election) QueryStakeOf(addr common.Address) ([]byte, error) {
+	return e.QueryStakeOfContext(e.ctx, addr)
+}
+
+// QueryStakeOfContext is the same as QueryStakeOf, but uses ctx for the RPC calls.
+func (e *Election) QueryStakeOfContext(ctx context.Context, addr common.Address) ([]byte, error) {
+	stake, err := e.vc.StakeAt(ctx, addr)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNoStake, addr.String())
+	} else if err != nil {
+		return nil, nodeError(err)
 	}
 
-	return json.Marshal(stake)
+	return json.Marshal(&stakeInfo{Stake: *stake, StakeCount: stakeAmount(stake.StakeCount)})
 }
 
 // QueryVote returns vote information of the account in json format, or an error if failed.
 func (e *Election) QueryVote() ([]byte, error) {
-	voter, err := e.vc.VoteAt(e.ctx, e.cfg.Sender)
-	if err != nil {
-		if err.Error() == errNotFound {
-			return nil, fmt.Errorf("no vote information for account: %s", e.cfg.Sender.String())
-		}
-		return nil, err
+	return e.QueryVoteContext(e.ctx)
+}
+
+// QueryVoteContext is the same as QueryVote, but uses ctx for the RPC calls.
+func (e *Election) QueryVoteContext(ctx context.Context) ([]byte, error) {
+	return e.QueryVoteOfContext(ctx, e.cfg.Sender)
+}
+
+// QueryVoteOf returns vote information of any account in json format, or an error if failed.
+func (e *Election) QueryVoteOf(addr common.Address) ([]byte, error) {
+	return e.QueryVoteOfContext(e.ctx, addr)
+}
+
+// QueryVoteOfContext is the same as QueryVoteOf, but uses ctx for the RPC calls.
+func (e *Election) QueryVoteOfContext(ctx context.Context, addr common.Address) ([]byte, error) {
+	voter, err := e.vc.VoteAt(ctx, addr)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrNotVoted, addr.String())
+	} else if err != nil {
+		return nil, nodeError(err)
 	}
 
 	return json.Marshal(voter)
@@ -36,18 +82,86 @@ func (e *Election) QueryVote() ([]byte, error) {
 
 // QueryCandidates returns a witnesses list in json format, or an error if failed.
 func (e *Election) QueryCandidates() ([]byte, error) {
-	candidates, err := e.vc.WitnessCandidates(e.ctx)
+	return e.QueryCandidatesContext(e.ctx)
+}
+
+// QueryCandidatesContext is the same as QueryCandidates, but uses ctx for the RPC calls.
+func (e *Election) QueryCandidatesContext(ctx context.Context) ([]byte, error) {
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("witness candidate list is empty")
+	} else if err != nil {
+		return nil, nodeError(err)
+	}
+
+	infos := make([]candidateInfo, 0, len(candidates))
+	for _, c := range candidates {
+		infos = append(infos, candidateInfo{
+			Candidate:       c,
+			TotalBounty:     NewAmount(c.TotalBounty.ToInt()),
+			ExtractedBounty: NewAmount(c.ExtractedBounty.ToInt()),
+		})
+	}
+	return json.Marshal(infos)
+}
+
+// QueryRestVNTBounty returns the rest vnt bounty, or an error if failed.
+func (e *Election) QueryRestVNTBounty() (*Amount, error) {
+	return e.QueryRestVNTBountyContext(e.ctx)
+}
+
+// QueryRestVNTBountyContext is the same as QueryRestVNTBounty, but uses ctx for the RPC calls.
+func (e *Election) QueryRestVNTBountyContext(ctx context.Context) (*Amount, error) {
+	rest, err := e.vc.RestVNTBounty(ctx)
 	if err != nil {
-		if err.Error() == errNotFound {
-			return nil, fmt.Errorf("witness candidate list is empty")
-		}
-		return nil, err
+		return nil, nodeError(err)
 	}
+	return NewAmount(rest), nil
+}
 
-	return json.Marshal(candidates)
+// QueryExtractableBounty returns the bounty which the account can extract now, or an error
+// if the account can not extract bounty.
+func (e *Election) QueryExtractableBounty() (*Amount, error) {
+	return e.QueryExtractableBountyContext(e.ctx)
 }
 
-// QueryRestVNTBounty returns a integer of the rest vnt bounty in wei, or an error if failed.
-func (e *Election) QueryRestVNTBounty() (*big.Int, error) {
-	return e.vc.RestVNTBounty(e.ctx)
+// QueryExtractableBountyContext is the same as QueryExtractableBounty, but uses ctx for the RPC calls.
+func (e *Election) QueryExtractableBountyContext(ctx context.Context) (*Amount, error) {
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+
+	// 账号是见证人候选人
+	for _, c := range candidates {
+		if c.Owner != e.cfg.Sender.String() {
+			continue
+		}
+
+		// 距离上次提取超过24小时
+		if c.LastExtractTime != nil {
+			next, remaining, err := e.cooldown(ctx, c.LastExtractTime.ToInt())
+			if err != nil {
+				return nil, err
+			}
+			if remaining > 0 {
+				return nil, &ErrExtractCooldown{NextAllowed: next, Remaining: remaining}
+			}
+		}
+
+		// 可提取的激励至少1000VNT
+		rest := big.NewInt(0)
+		if c.TotalBounty != nil {
+			rest.Set(c.TotalBounty.ToInt())
+		}
+		if c.ExtractedBounty != nil {
+			rest.Sub(rest, c.ExtractedBounty.ToInt())
+		}
+		if rest.Cmp(minExtractBounty) < 0 {
+			return nil, fmt.Errorf("%w, rest = %s", ErrBountyNotEnough, NewAmount(rest))
+		}
+		return NewAmount(rest), nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrNotCandidate, e.cfg.Sender.String())
 }
@@ -0,0 +1,167 @@
+package elect
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// defaultWitnessesNum is the number of witnesses of hubble network, used if WitnessesNum
+// of config is not set.
+const defaultWitnessesNum = 19
+
+// Ranking is the witness candidates sorted the same as the election contract, with the
+// witnesses selected by GetFirstNCandidates of the contract marked.
+type Ranking struct {
+	WitnessesNum int               `json:"witnessesNum"`
+	Selected     bool              `json:"selected"`   // false if the valid candidates are less than WitnessesNum, then no witness is selected
+	TotalVotes   *big.Int          `json:"totalVotes"` // votes of all the active candidates
+	Cutoff       *big.Int          `json:"cutoff"`     // votes of the last witness, nil if not selected
+	Candidates   []RankedCandidate `json:"candidates"`
+}
+
+// RankedCandidate is a witness candidate with its position in the ranking.
+type RankedCandidate struct {
+	Rank      int            `json:"rank"` // 1 is the first
+	Address   common.Address `json:"address"`
+	Name      string         `json:"name"`
+	Active    bool           `json:"active"`
+	Witness   bool           `json:"witness"` // in the witnesses selected by the contract
+	VoteCount *big.Int       `json:"voteCount"`
+	Share     float64        `json:"share"` // percent of VoteCount in TotalVotes
+	// CutoffGap is VoteCount minus the votes of the first candidate out of witnesses for a
+	// witness, or minus Cutoff for the others. It's nil if not selected or the candidate
+	// is inactive.
+	CutoffGap       *big.Int `json:"cutoffGap"`
+	Url             string   `json:"url"`
+	Website         string   `json:"website"`
+	TotalBounty     *Amount  `json:"totalBounty"`
+	ExtractedBounty *Amount  `json:"extractedBounty"`
+}
+
+// SetWitnessesNum sets the number of witnesses of the network used by the ranking.
+func (e *Election) SetWitnessesNum(n int) {
+	e.cfg.WitnessesNum = n
+}
+
+// RankCandidates returns the ranking of witness candidates, or an error if failed.
+func (e *Election) RankCandidates() (*Ranking, error) {
+	return e.RankCandidatesContext(e.ctx)
+}
+
+// RankCandidatesContext is the same as RankCandidates, but uses ctx for the RPC calls.
+func (e *Election) RankCandidatesContext(ctx context.Context) (*Ranking, error) {
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("witness candidate list is empty")
+	} else if err != nil {
+		return nil, nodeError(err)
+	}
+
+	n := e.cfg.WitnessesNum
+	if n <= 0 {
+		n = defaultWitnessesNum
+	}
+	return rankCandidates(candidates, n), nil
+}
+
+// rankCandidates returns the ranking of candidates with n witnesses, candidates is sorted.
+func rankCandidates(candidates []rpc.Candidate, n int) *Ranking {
+	sortCandidates(candidates)
+	r := &Ranking{WitnessesNum: n, TotalVotes: big.NewInt(0)}
+	for _, c := range candidates {
+		if c.Active {
+			r.TotalVotes.Add(r.TotalVotes, hexBig(c.VoteCount))
+		}
+	}
+
+	// 与合约的GetFirstNCandidates一致，依次选取有效的候选人，不足n个时不选取见证人
+	witnesses := make(map[int]bool)
+	last := -1
+	if len(candidates) >= n {
+		for i := 0; i < len(candidates) && len(witnesses) < n; i++ {
+			if candidates[i].Active && hexBig(candidates[i].VoteCount).Sign() >= 0 {
+				witnesses[i] = true
+				last = i
+			}
+		}
+	}
+	r.Selected = len(witnesses) == n && n > 0
+
+	// 见证人之外票数最多的有效候选人
+	runnerUp := big.NewInt(0)
+	if r.Selected {
+		r.Cutoff = hexBig(candidates[last].VoteCount)
+		for i := last + 1; i < len(candidates); i++ {
+			if candidates[i].Active {
+				runnerUp = hexBig(candidates[i].VoteCount)
+				break
+			}
+		}
+	}
+
+	for i, c := range candidates {
+		votes := hexBig(c.VoteCount)
+		rc := RankedCandidate{
+			Rank:            i + 1,
+			Address:         common.HexToAddress(c.Owner),
+			Name:            c.Name,
+			Active:          c.Active,
+			Witness:         r.Selected && witnesses[i],
+			VoteCount:       votes,
+			Url:             c.Url,
+			Website:         c.Website,
+			TotalBounty:     NewAmount(hexBig(c.TotalBounty)),
+			ExtractedBounty: NewAmount(hexBig(c.ExtractedBounty)),
+		}
+		if r.TotalVotes.Sign() > 0 {
+			share, _ := new(big.Float).Quo(new(big.Float).SetInt(votes), new(big.Float).SetInt(r.TotalVotes)).Float64()
+			rc.Share = share * 100
+		}
+		if r.Selected && c.Active {
+			if rc.Witness {
+				rc.CutoffGap = new(big.Int).Sub(votes, runnerUp)
+			} else {
+				rc.CutoffGap = new(big.Int).Sub(votes, r.Cutoff)
+			}
+		}
+		r.Candidates = append(r.Candidates, rc)
+	}
+	return r
+}
+
+// sortCandidates sorts the candidates the same as CandidateList of the election contract:
+// more votes first, and the smaller address first if votes are equal. The votes of
+// inactive candidates are treated as negative.
+func sortCandidates(candidates []rpc.Candidate) {
+	sort.Slice(candidates, func(i, j int) bool {
+		ret := rankVotes(&candidates[i]).Cmp(rankVotes(&candidates[j]))
+		if ret != 0 {
+			return ret > 0
+		}
+		return bytes.Compare(common.HexToAddress(candidates[i].Owner).Bytes(), common.HexToAddress(candidates[j].Owner).Bytes()) < 0
+	})
+}
+
+// rankVotes returns the votes of candidate used for sorting.
+func rankVotes(c *rpc.Candidate) *big.Int {
+	votes := hexBig(c.VoteCount)
+	if !c.Active {
+		votes.Neg(votes)
+	}
+	return votes
+}
+
+// hexBig returns a copy of the number, nil is treated as 0.
+func hexBig(h *hexutil.Big) *big.Int {
+	if h == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(h.ToInt())
+}
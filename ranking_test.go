@@ -0,0 +1,52 @@
+package elect
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+func testCandidate(owner string, votes int64, active bool) rpc.Candidate {
+	return rpc.Candidate{Owner: owner, Name: owner[len(owner)-1:], Active: active, VoteCount: (*hexutil.Big)(big.NewInt(votes))}
+}
+
+func TestRankCandidates(t *testing.T) {
+	candidates := []rpc.Candidate{
+		testCandidate("0x0000000000000000000000000000000000000005", 100, false),
+		testCandidate("0x0000000000000000000000000000000000000004", 30, true),
+		testCandidate("0x0000000000000000000000000000000000000003", 50, true),
+		testCandidate("0x0000000000000000000000000000000000000002", 30, true),
+		testCandidate("0x0000000000000000000000000000000000000001", 10, true),
+	}
+	r := rankCandidates(candidates, 3)
+	if !r.Selected || r.TotalVotes.Int64() != 120 || r.Cutoff.Int64() != 30 {
+		t.Fatalf("want selected with total votes 120 and cutoff 30, got: %v, %s, %s", r.Selected, r.TotalVotes, r.Cutoff)
+	}
+
+	// 票数相同时地址小的在前，无效的候选人排在最后
+	want := []struct {
+		name    string
+		witness bool
+		gap     int64
+	}{{"3", true, 40}, {"2", true, 20}, {"4", true, 20}, {"1", false, -20}, {"5", false, 0}}
+	for i, w := range want {
+		c := r.Candidates[i]
+		if c.Rank != i+1 || c.Name != w.name || c.Witness != w.witness {
+			t.Errorf("rank %d want candidate %s witness %v, got: %s %v", i+1, w.name, w.witness, c.Name, c.Witness)
+		}
+		if c.Active && c.CutoffGap.Int64() != w.gap || !c.Active && c.CutoffGap != nil {
+			t.Errorf("candidate %s want cutoff gap %d, got: %v", c.Name, w.gap, c.CutoffGap)
+		}
+	}
+	if share := r.Candidates[0].Share; share < 41.66 || share > 41.67 {
+		t.Errorf("want share 41.67%%, got: %f", share)
+	}
+
+	// 有效候选人不足时不选取见证人
+	r = rankCandidates(candidates, 5)
+	if r.Selected || r.Cutoff != nil || r.Candidates[0].Witness {
+		t.Errorf("want no witness selected, got: %+v", r)
+	}
+}
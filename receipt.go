@@ -0,0 +1,66 @@
+package elect
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+
+	hubble "github.com/vntchain/go-vnt"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/common/hexutil"
+	"github.com/vntchain/go-vnt/core/types"
+)
+
+// receiptPollInterval is the interval of querying transaction receipt.
+var receiptPollInterval = time.Second
+
+// TxResult is the on-chain result of a mined transaction.
+type TxResult struct {
+	Hash        common.Hash  `json:"hash"`
+	BlockNumber *big.Int     `json:"blockNumber"`
+	GasUsed     uint64       `json:"gasUsed"`
+	Success     bool         `json:"success"`
+	Logs        []*types.Log `json:"logs"`
+}
+
+// WaitMined waits until the transaction of hash is mined, and returns the result decoded
+// from the receipt, or an error if ctx is done before the transaction is mined.
+func (e *Election) WaitMined(ctx context.Context, hash common.Hash) (*TxResult, error) {
+	return e.WaitAnyMined(ctx, hash)
+}
+
+// transactionResult returns the result of transaction, block number is not a field of
+// types.Receipt, so decode the raw receipt twice.
+func (e *Election) transactionResult(ctx context.Context, hash common.Hash) (*TxResult, error) {
+	var raw json.RawMessage
+	if err := e.vc.CallContext(ctx, &raw, "core_getTransactionReceipt", hash); err != nil {
+		return nil, nodeError(err)
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, hubble.NotFound
+	}
+
+	var receipt types.Receipt
+	if err := json.Unmarshal(raw, &receipt); err != nil {
+		return nil, fmt.Errorf("decode receipt of transaction %s error: %s", hash.String(), err)
+	}
+	var block struct {
+		BlockNumber *hexutil.Big `json:"blockNumber"`
+	}
+	if err := json.Unmarshal(raw, &block); err != nil {
+		return nil, fmt.Errorf("decode receipt of transaction %s error: %s", hash.String(), err)
+	}
+
+	ret := &TxResult{
+		Hash:    hash,
+		GasUsed: receipt.GasUsed,
+		Success: receipt.Status == types.ReceiptStatusSuccessful,
+		Logs:    receipt.Logs,
+	}
+	if block.BlockNumber != nil {
+		ret.BlockNumber = block.BlockNumber.ToInt()
+	}
+	return ret, nil
+}
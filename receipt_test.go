@@ -0,0 +1,38 @@
+package elect
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestWaitMined(t *testing.T) {
+	receiptPollInterval = 10 * time.Millisecond
+	core := newFakeCore()
+	e := newTestElection(t, core)
+
+	// the transaction is not mined before ctx is done
+	hash := common.HexToHash("0x01")
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if ret, err := e.WaitMined(ctx, hash); err == nil {
+		t.Errorf("wait pending transaction want error, got: %+v", ret)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		core.mine(hash, 5, true)
+	}()
+	ret, err := e.WaitMined(context.Background(), hash)
+	if err != nil || !ret.Success || ret.Hash != hash || ret.BlockNumber == nil || ret.BlockNumber.Uint64() != 5 || ret.GasUsed != 21000 {
+		t.Errorf("want the transaction succeeded in block 5, got: %+v, %v", ret, err)
+	}
+
+	reverted := common.HexToHash("0x02")
+	core.mine(reverted, 6, false)
+	if ret, err := e.WaitMined(context.Background(), reverted); err != nil || ret.Success || ret.BlockNumber.Uint64() != 6 {
+		t.Errorf("want the transaction failed in block 6, got: %+v, %v", ret, err)
+	}
+}
@@ -0,0 +1,116 @@
+package elect
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/params"
+)
+
+// defaultPriceBump is the minimum percent of gas price increased to replace a
+// pending transaction, it's the default price bump of transaction pool.
+const defaultPriceBump = 10
+
+// SpeedUp replaces the pending transaction of hash with a transaction has the same nonce
+// and payload at a higher gas price, returns the hash of the new transaction, or an error
+// if failed.
+func (e *Election) SpeedUp(hash common.Hash) (common.Hash, error) {
+	return e.SpeedUpContext(e.ctx, hash)
+}
+
+// SpeedUpContext is the same as SpeedUp, but uses ctx for the RPC calls.
+func (e *Election) SpeedUpContext(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	tx, gasPrice, err := e.pendingTxToReplace(ctx, hash)
+	if err != nil {
+		return emptyHash, err
+	}
+
+	newTx := types.NewTransaction(tx.Nonce(), *tx.To(), tx.Value(), tx.Gas(), gasPrice, tx.Data())
+	return e.signAndSendTx(ctx, newTx)
+}
+
+// Cancel replaces the pending transaction of hash with a zero value transfer to yourself
+// which has the same nonce at a higher gas price, returns the hash of the new transaction,
+// or an error if failed.
+func (e *Election) Cancel(hash common.Hash) (common.Hash, error) {
+	return e.CancelContext(e.ctx, hash)
+}
+
+// CancelContext is the same as Cancel, but uses ctx for the RPC calls.
+func (e *Election) CancelContext(ctx context.Context, hash common.Hash) (common.Hash, error) {
+	tx, gasPrice, err := e.pendingTxToReplace(ctx, hash)
+	if err != nil {
+		return emptyHash, err
+	}
+
+	newTx := types.NewTransaction(tx.Nonce(), e.cfg.Sender, common.Big0, params.TxGas, gasPrice, nil)
+	return e.signAndSendTx(ctx, newTx)
+}
+
+// WaitAnyMined waits until one of the transactions is mined, returns the result of the
+// mined transaction, or an error if ctx is done before any transaction is mined. It's
+// used to track a transaction and its replacements.
+func (e *Election) WaitAnyMined(ctx context.Context, hashes ...common.Hash) (*TxResult, error) {
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, hash := range hashes {
+			ret, err := e.transactionResult(ctx, hash)
+			if err == nil {
+				return ret, nil
+			} else if !isNotFound(err) {
+				return nil, err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait transactions %v mined: %s", hashes, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// pendingTxToReplace returns the pending transaction of hash sent by the account, and the
+// gas price to replace it, which is the larger one of the bumped gas price and the gas
+// price decided by the gas strategy.
+func (e *Election) pendingTxToReplace(ctx context.Context, hash common.Hash) (*types.Transaction, *big.Int, error) {
+	tx, isPending, err := e.vc.TransactionByHash(ctx, hash)
+	if isNotFound(err) {
+		return nil, nil, fmt.Errorf("transaction %s is not found", hash.String())
+	} else if err != nil {
+		return nil, nil, nodeError(err)
+	}
+	if !isPending {
+		return nil, nil, fmt.Errorf("transaction %s is already mined", hash.String())
+	}
+
+	from, err := types.Sender(types.NewEIP155Signer(big.NewInt(int64(e.cfg.ChainID))), tx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("recover sender of transaction %s error: %s", hash.String(), err)
+	}
+	if from != e.cfg.Sender {
+		return nil, nil, fmt.Errorf("transaction %s is sent by %s, not the account of config: %s", hash.String(), from.String(), e.cfg.Sender.String())
+	}
+
+	bump := e.cfg.Gas.PriceBump
+	if bump == 0 {
+		bump = defaultPriceBump
+	}
+	gasPrice := new(big.Int).Mul(tx.GasPrice(), big.NewInt(int64(100+bump)))
+	gasPrice.Div(gasPrice, big.NewInt(100))
+
+	price, err := e.gasPrice(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	if price.Cmp(gasPrice) > 0 {
+		gasPrice = price
+	}
+	return tx, gasPrice, nil
+}
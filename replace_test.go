@@ -0,0 +1,84 @@
+package elect
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+	"github.com/vntchain/go-vnt/crypto"
+	"github.com/vntchain/go-vnt/params"
+	"github.com/vntchain/go-vnt/rlp"
+)
+
+func TestSpeedUpAndCancel(t *testing.T) {
+	receiptPollInterval = 10 * time.Millisecond
+	core := newFakeCore()
+	e := newTestElection(t, core)
+	key, _ := crypto.GenerateKey()
+	e.cfg.Sender = crypto.PubkeyToAddress(key.PublicKey)
+
+	// send sends the transaction signed by key to the fake node
+	send := func(tx *types.Transaction, key *ecdsa.PrivateKey) common.Hash {
+		t.Helper()
+		signed, err := types.SignTx(tx, types.NewEIP155Signer(big.NewInt(int64(e.cfg.ChainID))), key)
+		if err != nil {
+			t.Fatalf("sign tx error: %s", err)
+		}
+		raw, _ := rlp.EncodeToBytes(signed)
+		if _, err := core.SendRawTransaction(context.Background(), raw); err != nil {
+			t.Fatalf("send tx error: %s", err)
+		}
+		return signed.Hash()
+	}
+
+	utx, err := e.BuildUnsignedTx(func() (common.Hash, error) { return e.Stake("10") })
+	if err != nil {
+		t.Fatalf("build stake tx error: %s", err)
+	}
+	orig := utx.Transaction()
+	staked := send(orig, key)
+
+	// speedup keeps the nonce and payload at the bumped gas price
+	bumped := new(big.Int).Div(new(big.Int).Mul(orig.GasPrice(), big.NewInt(110)), big.NewInt(100))
+	tx, err := e.captureTx(func() (common.Hash, error) { return e.SpeedUp(staked) })
+	if err != nil {
+		t.Fatalf("speedup error: %s", err)
+	}
+	if tx.Nonce() != orig.Nonce() || *tx.To() != *orig.To() || tx.Value().Cmp(orig.Value()) != 0 ||
+		string(tx.Data()) != string(orig.Data()) || tx.Gas() != orig.Gas() || tx.GasPrice().Cmp(bumped) != 0 {
+		t.Errorf("want the same nonce and payload at gas price %s, got: %+v", bumped, tx)
+	}
+
+	// cancel is a zero value transfer to yourself at the same nonce
+	tx, err = e.captureTx(func() (common.Hash, error) { return e.Cancel(staked) })
+	if err != nil {
+		t.Fatalf("cancel error: %s", err)
+	}
+	if tx.Nonce() != orig.Nonce() || *tx.To() != e.cfg.Sender || tx.Value().Sign() != 0 ||
+		len(tx.Data()) != 0 || tx.Gas() != params.TxGas || tx.GasPrice().Cmp(bumped) != 0 {
+		t.Errorf("want a zero value transfer to %s at nonce %d, got: %+v", e.cfg.Sender.String(), orig.Nonce(), tx)
+	}
+	canceled := send(tx, key)
+
+	// the transaction of another account can't be replaced
+	other, _ := crypto.GenerateKey()
+	if _, err := e.captureTx(func() (common.Hash, error) { return e.SpeedUp(send(orig, other)) }); err == nil {
+		t.Errorf("speedup transaction of another account want error, got nil")
+	}
+
+	// one of the transactions is mined
+	core.mine(canceled, 7, true)
+	ctx, done := context.WithTimeout(context.Background(), time.Second)
+	defer done()
+	ret, err := e.WaitAnyMined(ctx, staked, canceled)
+	if err != nil || ret.Hash != canceled || !ret.Success {
+		t.Errorf("want the cancel transaction %s mined, got: %+v, %v", canceled.String(), ret, err)
+	}
+	if _, err := e.captureTx(func() (common.Hash, error) { return e.SpeedUp(canceled) }); err == nil {
+		t.Errorf("speedup mined transaction want error, got nil")
+	}
+}
@@ -0,0 +1,60 @@
+package elect
+
+import (
+	"context"
+
+	hubble "github.com/vntchain/go-vnt"
+)
+
+// SimulateResult is the result of simulating an election operation.
+type SimulateResult struct {
+	Method  string   `json:"method"`
+	Args    []string `json:"args"`
+	Success bool     `json:"success"`
+	Gas     uint64   `json:"gas"`             // estimated gas, 0 if the operation would fail
+	Error   string   `json:"error,omitempty"` // error reported by node
+	Err     error    `json:"-"`               // Error as ErrContract or ErrRPC, nil if the operation would succeed
+}
+
+// Simulate runs op against the pending state of the node without signing and sending,
+// reports whether it would succeed and the gas it would use, or an error if op failed
+// in the checks before creating transaction.
+func (e *Election) Simulate(op Op) (*SimulateResult, error) {
+	return e.SimulateContext(e.ctx, op)
+}
+
+// SimulateContext is the same as Simulate, but uses ctx for the RPC calls of simulation,
+// the checks run by op use the context of op.
+func (e *Election) SimulateContext(ctx context.Context, op Op) (*SimulateResult, error) {
+	unSignTx, err := e.captureTx(op)
+	if err != nil {
+		return nil, err
+	}
+
+	method, args, err := decodeInput(unSignTx.Data())
+	if err != nil {
+		return nil, err
+	}
+	ret := &SimulateResult{Method: method, Args: args}
+
+	msg := hubble.CallMsg{
+		From:     e.cfg.Sender,
+		To:       unSignTx.To(),
+		GasPrice: unSignTx.GasPrice(),
+		Value:    unSignTx.Value(),
+		Data:     unSignTx.Data(),
+	}
+	if _, err := e.vc.PendingCallContract(ctx, msg); err != nil {
+		ret.Error, ret.Err = err.Error(), contractError(err)
+		return ret, nil
+	}
+	// 调用失败时节点不返回合约的错误，只能通过估算gas判断是否成功
+	if ret.Gas, err = e.vc.EstimateGas(ctx, msg); err != nil {
+		ret.Gas = 0
+		ret.Error, ret.Err = err.Error(), contractError(err)
+		return ret, nil
+	}
+
+	ret.Success = true
+	return ret, nil
+}
@@ -0,0 +1,34 @@
+package elect
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/vntchain/go-vnt/common"
+)
+
+func TestSimulate(t *testing.T) {
+	core := newFakeCore()
+	e := newTestElection(t, core)
+	stake := func() (common.Hash, error) { return e.Stake("10") }
+
+	ret, err := e.Simulate(stake)
+	if err != nil || !ret.Success || ret.Gas != 21000 || ret.Method != "$stake" {
+		t.Errorf("want simulating stake succeeded, got: %+v, %v", ret, err)
+	}
+
+	core.callErr = errors.New("evm: execution reverted")
+	ret, err = e.Simulate(stake)
+	if err != nil || ret.Success || ret.Gas != 0 || ret.Error == "" {
+		t.Errorf("want simulating stake failed with the error of node, got: %+v, %v", ret, err)
+	}
+
+	// 创建交易前的检查失败时返回错误
+	if _, err := e.Simulate(func() (common.Hash, error) { return e.Stake("1000") }); err == nil {
+		t.Errorf("want error of staking more than balance, got nil")
+	}
+
+	if len(core.sent) != 0 {
+		t.Errorf("want no transaction sent by simulating, got %d", len(core.sent))
+	}
+}
@@ -0,0 +1,175 @@
+package elect
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// Status is the election position of an account at the latest block.
+type Status struct {
+	Address   common.Address `json:"address"`
+	ChainTime time.Time      `json:"chainTime"` // time of the latest block
+	Balance   *Amount        `json:"balance"`
+
+	// Stake, zero if the account has no stake
+	Stake          *Amount    `json:"stake"`
+	UnstakeAllowed *time.Time `json:"unstakeAllowed"` // nil if the account has no stake
+
+	// Vote, the votes are frozen at the last vote, VoteWeight is the votes of voting now
+	LastVoteCount   *big.Int         `json:"lastVoteCount"`
+	VoteWeight      *big.Int         `json:"voteWeight"`
+	VotedCandidates []VotedCandidate `json:"votedCandidates"` // voted by the account, or by its proxy
+	NextVoteAllowed *time.Time       `json:"nextVoteAllowed"` // of voting and setting proxy, nil if never voted
+	Proxy           *common.Address  `json:"proxy"`           // the proxy voting for the account
+	IsProxy         bool             `json:"isProxy"`
+	ProxyVoteCount  *big.Int         `json:"proxyVoteCount"` // votes delegated to the account as a proxy
+	Candidate       *CandidateStatus `json:"candidate"`      // nil if the account is not a candidate
+}
+
+// VotedCandidate is a candidate voted by the account.
+type VotedCandidate struct {
+	Address   common.Address `json:"address"`
+	Name      string         `json:"name"`
+	Rank      int            `json:"rank"` // 1 is the first, 0 if it's not found
+	Active    bool           `json:"active"`
+	VoteCount *big.Int       `json:"voteCount"`
+}
+
+// CandidateStatus is the position of the account as a witness candidate.
+type CandidateStatus struct {
+	Name               string     `json:"name"`
+	Rank               int        `json:"rank"`
+	Active             bool       `json:"active"`
+	VoteCount          *big.Int   `json:"voteCount"`
+	ExtractableBounty  *Amount    `json:"extractableBounty"`  // the rest of bounty, zero if it's less than 1000 VNT
+	NextExtractAllowed *time.Time `json:"nextExtractAllowed"` // nil if never extracted
+}
+
+// Status returns the election position of the account, or an error if failed.
+func (e *Election) Status() (*Status, error) {
+	return e.StatusContext(e.ctx)
+}
+
+// StatusContext is the same as Status, but uses ctx for the RPC calls.
+func (e *Election) StatusContext(ctx context.Context) (*Status, error) {
+	return e.StatusOfContext(ctx, e.cfg.Sender)
+}
+
+// StatusOf returns the election position of any account, or an error if failed.
+func (e *Election) StatusOf(addr common.Address) (*Status, error) {
+	return e.StatusOfContext(e.ctx, addr)
+}
+
+// StatusOfContext is the same as StatusOf, but uses ctx for the RPC calls.
+func (e *Election) StatusOfContext(ctx context.Context, addr common.Address) (*Status, error) {
+	now, err := e.chainTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balance, err := e.vc.BalanceAt(ctx, addr, nil)
+	if err != nil {
+		return nil, nodeError(err)
+	}
+	s := &Status{
+		Address:        addr,
+		ChainTime:      now,
+		Balance:        NewAmount(balance),
+		Stake:          NewAmount(nil),
+		LastVoteCount:  big.NewInt(0),
+		VoteWeight:     big.NewInt(0),
+		ProxyVoteCount: big.NewInt(0),
+	}
+
+	// 抵押，取回抵押后记录仍然存在，数量为0
+	stake, err := e.vc.StakeAt(ctx, addr)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+	if stake != nil && stake.StakeCount != nil && stake.StakeCount.Sign() > 0 {
+		s.Stake = stakeAmount(stake.StakeCount)
+		s.VoteWeight = voteCount(stake.StakeCount, now)
+		unstake := e.nextAllowed(stake.LastStakeTimeStamp)
+		s.UnstakeAllowed = &unstake
+	}
+
+	candidates, err := e.vc.WitnessCandidates(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+	sortCandidates(candidates)
+
+	// 投票和代理
+	voter, err := e.vc.VoteAt(ctx, addr)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+	if voter != nil {
+		if voter.LastVoteCount != nil {
+			s.LastVoteCount = voter.LastVoteCount
+		}
+		if voter.ProxyVoteCount != nil {
+			s.ProxyVoteCount = voter.ProxyVoteCount
+		}
+		s.IsProxy = voter.IsProxy
+		if voter.LastVoteTimeStamp != nil && voter.LastVoteTimeStamp.Sign() > 0 {
+			next := e.nextAllowed(voter.LastVoteTimeStamp)
+			s.NextVoteAllowed = &next
+		}
+
+		voted := voter.VoteCandidates
+		if voter.Proxy != emptyAddr {
+			proxy := voter.Proxy
+			s.Proxy = &proxy
+			pv, err := e.vc.VoteAt(ctx, proxy)
+			if err != nil && !isNotFound(err) {
+				return nil, nodeError(err)
+			}
+			if pv != nil {
+				voted = pv.VoteCandidates
+			}
+		}
+		for _, candidate := range voted {
+			vc := VotedCandidate{Address: candidate, VoteCount: big.NewInt(0)}
+			if rank, c := findCandidate(candidates, candidate); c != nil {
+				vc.Name, vc.Rank, vc.Active, vc.VoteCount = c.Name, rank, c.Active, hexBig(c.VoteCount)
+			}
+			s.VotedCandidates = append(s.VotedCandidates, vc)
+		}
+	}
+
+	// 见证人候选人
+	if rank, c := findCandidate(candidates, addr); c != nil {
+		// 可提取的激励至少1000VNT
+		rest := new(big.Int).Sub(hexBig(c.TotalBounty), hexBig(c.ExtractedBounty))
+		if rest.Cmp(minExtractBounty) < 0 {
+			rest.SetInt64(0)
+		}
+		s.Candidate = &CandidateStatus{
+			Name:              c.Name,
+			Rank:              rank,
+			Active:            c.Active,
+			VoteCount:         hexBig(c.VoteCount),
+			ExtractableBounty: NewAmount(rest),
+		}
+		if c.LastExtractTime != nil && c.LastExtractTime.ToInt().Sign() > 0 {
+			next := e.nextAllowed(c.LastExtractTime.ToInt())
+			s.Candidate.NextExtractAllowed = &next
+		}
+	}
+	return s, nil
+}
+
+// findCandidate returns the rank and the candidate of the address in the sorted
+// candidates, or nil if not found.
+func findCandidate(candidates []rpc.Candidate, addr common.Address) (int, *rpc.Candidate) {
+	for i := range candidates {
+		if common.HexToAddress(candidates[i].Owner) == addr {
+			return i + 1, &candidates[i]
+		}
+	}
+	return 0, nil
+}
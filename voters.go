@@ -0,0 +1,137 @@
+package elect
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+// voteMethods are the methods of election contract changing the votes of the sender.
+var voteMethods = []string{"voteWitnesses", "setProxy", "cancelVote", "cancelProxy"}
+
+// CandidateVoters is the voters of a candidate found from the indexed transactions,
+// with the vote state at the latest block.
+type CandidateVoters struct {
+	Candidate  common.Address `json:"candidate"`
+	Name       string         `json:"name"`
+	Active     bool           `json:"active"`
+	VoteCount  *big.Int       `json:"voteCount"`  // votes of the candidate in contract
+	TotalVotes *big.Int       `json:"totalVotes"` // votes of the direct voters found, less than VoteCount if the index is incomplete
+	IndexedTo  uint64         `json:"indexedTo"`  // the last indexed block
+	Voters     []*Voter       `json:"voters"`     // direct voters, then the delegators of each proxy
+}
+
+// Voter is a voter of the candidate. A direct voter votes for the candidate by itself,
+// its Votes is the sum of its LastVoteCount and ProxyVoteCount. A delegator sets a proxy
+// voting for the candidate, its Votes is its LastVoteCount, which is a part of the
+// ProxyVoteCount of the proxy.
+type Voter struct {
+	Address        common.Address  `json:"address"`
+	Proxy          *common.Address `json:"proxy"` // nil for direct voter
+	LastVoteCount  *big.Int        `json:"lastVoteCount"`
+	ProxyVoteCount *big.Int        `json:"proxyVoteCount"` // votes delegated to the voter as a proxy
+	Votes          *big.Int        `json:"votes"`
+	LastVoteTime   *time.Time      `json:"lastVoteTime"`
+}
+
+// Voters returns the voters of the candidate. The contract only records the voted
+// candidates of each voter, so the accounts are found from the indexed transactions
+// of voting and proxy, then their votes are read at the latest block.
+func (ix *Index) Voters(ctx context.Context, candidate common.Address) (*CandidateVoters, error) {
+	txs, err := ix.Txs(&TxFilter{Methods: voteMethods})
+	if err != nil {
+		return nil, err
+	}
+	cv := &CandidateVoters{Candidate: candidate, VoteCount: big.NewInt(0), TotalVotes: big.NewInt(0)}
+	cv.IndexedTo, _, _ = ix.Head()
+
+	candidates, err := ix.e.vc.WitnessCandidates(ctx)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+	if _, c := findCandidate(candidates, candidate); c != nil {
+		cv.Name, cv.Active, cv.VoteCount = c.Name, c.Active, hexBig(c.VoteCount)
+	}
+
+	// 合约中没有候选人到投票人的索引，读取索引中出现过的账户的当前投票状态
+	seen := make(map[common.Address]bool)
+	var direct []*Voter
+	delegators := make(map[common.Address][]*Voter)
+	for _, tx := range txs {
+		if !tx.Success || seen[tx.From] {
+			continue
+		}
+		seen[tx.From] = true
+		v, err := ix.e.vc.VoteAt(ctx, tx.From)
+		if isNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, nodeError(err)
+		}
+		voter := newVoter(v)
+		if v.Proxy != emptyAddr {
+			voter.Proxy = &v.Proxy
+			delegators[v.Proxy] = append(delegators[v.Proxy], voter)
+		} else if votedFor(v, candidate) {
+			direct = append(direct, voter)
+		}
+	}
+
+	sortVoters(direct)
+	for _, voter := range direct {
+		cv.TotalVotes.Add(cv.TotalVotes, voter.Votes)
+		cv.Voters = append(cv.Voters, voter)
+	}
+	for _, voter := range direct {
+		ds := delegators[voter.Address]
+		sortVoters(ds)
+		cv.Voters = append(cv.Voters, ds...)
+	}
+	return cv, nil
+}
+
+// newVoter returns the Voter of the vote information, the votes are counted as a direct
+// voter.
+func newVoter(v *rpc.Voter) *Voter {
+	voter := &Voter{Address: v.Owner, LastVoteCount: big.NewInt(0), ProxyVoteCount: big.NewInt(0)}
+	if v.LastVoteCount != nil {
+		voter.LastVoteCount = v.LastVoteCount
+	}
+	if v.ProxyVoteCount != nil {
+		voter.ProxyVoteCount = v.ProxyVoteCount
+	}
+	if v.LastVoteTimeStamp != nil && v.LastVoteTimeStamp.Sign() > 0 {
+		t := time.Unix(v.LastVoteTimeStamp.Int64(), 0)
+		voter.LastVoteTime = &t
+	}
+	voter.Votes = new(big.Int).Add(voter.LastVoteCount, voter.ProxyVoteCount)
+	if v.Proxy != emptyAddr {
+		voter.Votes = voter.LastVoteCount
+	}
+	return voter
+}
+
+// votedFor returns true if the voter votes for the candidate by itself.
+func votedFor(v *rpc.Voter, candidate common.Address) bool {
+	for _, c := range v.VoteCandidates {
+		if c == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// sortVoters sorts the voters by votes descending, then by address.
+func sortVoters(voters []*Voter) {
+	sort.SliceStable(voters, func(i, j int) bool {
+		if c := voters[i].Votes.Cmp(voters[j].Votes); c != 0 {
+			return c > 0
+		}
+		return bytes.Compare(voters[i].Address.Bytes(), voters[j].Address.Bytes()) < 0
+	})
+}
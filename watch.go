@@ -0,0 +1,299 @@
+package elect
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	hubble "github.com/vntchain/go-vnt"
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/core/types"
+)
+
+// defaultPollInterval is the interval of polling the latest block if the node doesn't
+// support subscribing new heads, such as HTTP.
+const defaultPollInterval = 5 * time.Second
+
+// Rules of the alerts of watching.
+const (
+	AlertNearCutoff        = "near_cutoff"        // the candidate is within the margin of the cutoff
+	AlertDroppedOut        = "dropped_out"        // the candidate dropped out of the top N
+	AlertCandidateInactive = "candidate_inactive" // a voted candidate is inactive
+	AlertBountyExtractable = "bounty_extractable" // the bounty of the account can be extracted
+	AlertAccountChanged    = "account_changed"    // the stake, votes or proxy of the account changed
+	AlertNodeError         = "node_error"         // querying the node failed
+)
+
+// WatchRules is the rules of watching the election state. An alert is raised when the
+// condition of a rule becomes true, and raised again only after the condition becomes
+// false and then true.
+type WatchRules struct {
+	Account      common.Address `json:"account"`      // the account whose stake, votes and bounty are watched, default is the sender
+	Candidate    common.Address `json:"candidate"`    // the candidate whose rank is watched, default is the account if it's a candidate
+	CutoffMargin *big.Int       `json:"cutoffMargin"` // votes from the cutoff to raise near_cutoff, nil disables it
+	TopN         int            `json:"topN"`         // raise dropped_out if the candidate is not in the top N, default is the number of witnesses
+	Every        uint64         `json:"every"`        // blocks between checks, default 1
+	PollInterval int            `json:"pollInterval"` // seconds of polling the latest block over HTTP, default 5
+	Disable      []string       `json:"disable"`      // the rules disabled
+	Alerts       []string       `json:"alerts"`       // targets of alerts, see NewAlertSink, default is stdout
+}
+
+// Alert is raised when the condition of a rule becomes true.
+type Alert struct {
+	Rule    string         `json:"rule"`
+	Block   uint64         `json:"block"`
+	Time    time.Time      `json:"time"`    // time of the block
+	Subject common.Address `json:"subject"` // the candidate or account of the alert
+	Message string         `json:"message"`
+}
+
+// watchState is the election state checked at a block.
+type watchState struct {
+	number  uint64
+	time    time.Time
+	ranking *Ranking
+	status  *Status // nil if no account is watched
+}
+
+// headSubscriber is implemented by the Backend supporting subscribing new heads, such as
+// the node connected by websocket.
+type headSubscriber interface {
+	SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (hubble.Subscription, error)
+}
+
+// Watch checks the election state at every new block, or every rules.Every blocks, and
+// calls alert for each alert raised, until ctx is done. It subscribes new heads if the
+// node supports it, or polls the latest block otherwise. The failures of querying the
+// node are raised as node_error alerts instead of stopping watching.
+func (e *Election) Watch(ctx context.Context, rules *WatchRules, alert func(*Alert)) error {
+	every := rules.Every
+	if every == 0 {
+		every = 1
+	}
+	interval := defaultPollInterval
+	if rules.PollInterval > 0 {
+		interval = time.Duration(rules.PollInterval) * time.Second
+	}
+
+	// 节点支持订阅时订阅新区块，否则轮询最新区块
+	heads := make(chan *types.Header, 16)
+	var sub hubble.Subscription
+	if s, ok := e.vc.b.(headSubscriber); ok {
+		var err error
+		if sub, err = s.SubscribeNewHead(ctx, heads); err != nil {
+			e.log.Info("Subscribe new heads failed, polling the latest block", "err", err)
+			sub = nil
+		}
+	}
+	defer func() {
+		if sub != nil {
+			sub.Unsubscribe()
+		}
+	}()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		prev    *watchState
+		raised  = make(map[string]bool)
+		last    uint64
+		checked bool
+		failed  bool
+	)
+	check := func(number uint64) {
+		if checked && number < last+every {
+			return
+		}
+		cur, err := e.watchState(ctx, rules, number)
+		if err != nil {
+			if ctx.Err() == nil && !failed {
+				alert(&Alert{Rule: AlertNodeError, Block: number, Time: time.Now(), Message: fmt.Sprintf("query election state error: %s", err)})
+			}
+			failed = true
+			return
+		}
+		failed = false
+		for _, a := range watchAlerts(prev, cur, rules, raised) {
+			alert(a)
+		}
+		prev, last, checked = cur, number, true
+	}
+	poll := func() {
+		h, err := e.vc.HeaderByNumber(ctx, nil)
+		if err != nil {
+			if ctx.Err() == nil && !failed {
+				alert(&Alert{Rule: AlertNodeError, Block: last, Time: time.Now(), Message: fmt.Sprintf("query the latest block error: %s", nodeError(err))})
+			}
+			failed = true
+			return
+		}
+		check(h.Number.Uint64())
+	}
+
+	poll()
+	for {
+		var subErr <-chan error
+		if sub != nil {
+			subErr = sub.Err()
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subErr:
+			e.log.Info("Subscription of new heads failed, polling the latest block", "err", err)
+			sub = nil
+		case h := <-heads:
+			check(h.Number.Uint64())
+		case <-ticker.C:
+			if sub == nil {
+				poll()
+			}
+		}
+	}
+}
+
+// watchState returns the election state at the latest block.
+func (e *Election) watchState(ctx context.Context, rules *WatchRules, number uint64) (*watchState, error) {
+	st := &watchState{number: number}
+	var err error
+	if st.ranking, err = e.RankCandidatesContext(ctx); err != nil {
+		return nil, err
+	}
+	account := rules.Account
+	if account == emptyAddr {
+		account = e.cfg.Sender
+	}
+	if account != emptyAddr {
+		if st.status, err = e.StatusOfContext(ctx, account); err != nil {
+			return nil, err
+		}
+		st.time = st.status.ChainTime
+	} else if st.time, err = e.chainTime(ctx); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+// watchAlerts returns the alerts of the rules whose conditions become true at cur, and
+// the changes of the account from prev. raised records the conditions true at prev.
+func watchAlerts(prev, cur *watchState, rules *WatchRules, raised map[string]bool) []*Alert {
+	disabled := make(map[string]bool)
+	for _, r := range rules.Disable {
+		disabled[r] = true
+	}
+
+	conditions := watchConditions(cur, rules)
+	var alerts []*Alert
+	for key, a := range conditions {
+		if !raised[key] && !disabled[a.Rule] {
+			alerts = append(alerts, a)
+		}
+	}
+	for key := range raised {
+		delete(raised, key)
+	}
+	for key := range conditions {
+		raised[key] = true
+	}
+	if prev != nil && prev.status != nil && cur.status != nil && !disabled[AlertAccountChanged] {
+		if changes := accountChanges(prev.status, cur.status); len(changes) > 0 {
+			alerts = append(alerts, cur.alert(AlertAccountChanged, cur.status.Address, "account %s changed: %s", cur.status.Address.String(), strings.Join(changes, ", ")))
+		}
+	}
+	sort.SliceStable(alerts, func(i, j int) bool { return alerts[i].Rule < alerts[j].Rule })
+	return alerts
+}
+
+// watchConditions returns the alerts of the conditions true at the state, keyed by the
+// rule and subject.
+func watchConditions(st *watchState, rules *WatchRules) map[string]*Alert {
+	conditions := make(map[string]*Alert)
+	add := func(a *Alert) { conditions[a.Rule+":"+a.Subject.String()] = a }
+
+	candidate := rules.Candidate
+	if candidate == emptyAddr && st.status != nil && st.status.Candidate != nil {
+		candidate = st.status.Address
+	}
+	if candidate != emptyAddr {
+		var c *RankedCandidate
+		for i := range st.ranking.Candidates {
+			if st.ranking.Candidates[i].Address == candidate {
+				c = &st.ranking.Candidates[i]
+			}
+		}
+		topN := rules.TopN
+		if topN <= 0 {
+			topN = st.ranking.WitnessesNum
+		}
+		switch {
+		case c == nil:
+			add(st.alert(AlertDroppedOut, candidate, "%s is not a witness candidate", candidate.String()))
+		case !c.Active:
+			add(st.alert(AlertDroppedOut, candidate, "candidate %s is inactive", c.Name))
+		case c.Rank > topN:
+			add(st.alert(AlertDroppedOut, candidate, "candidate %s dropped out of the top %d, rank %d", c.Name, topN, c.Rank))
+		}
+		if c != nil && c.CutoffGap != nil && rules.CutoffMargin != nil && new(big.Int).Abs(c.CutoffGap).Cmp(rules.CutoffMargin) <= 0 {
+			add(st.alert(AlertNearCutoff, candidate, "candidate %s (rank %d) is %s votes from the cutoff", c.Name, c.Rank, c.CutoffGap))
+		}
+	}
+
+	if s := st.status; s != nil {
+		for _, vc := range s.VotedCandidates {
+			if !vc.Active {
+				add(st.alert(AlertCandidateInactive, vc.Address, "voted candidate %s (%s) is inactive", vc.Name, vc.Address.String()))
+			}
+		}
+		if c := s.Candidate; c != nil && c.ExtractableBounty.Wei().Cmp(minExtractBounty) >= 0 &&
+			(c.NextExtractAllowed == nil || !c.NextExtractAllowed.After(s.ChainTime)) {
+			add(st.alert(AlertBountyExtractable, s.Address, "bounty %s can be extracted", c.ExtractableBounty))
+		}
+	}
+	return conditions
+}
+
+// accountChanges returns the readable changes of the account.
+func accountChanges(prev, cur *Status) []string {
+	var changes []string
+	if prev.Stake.Cmp(cur.Stake) != 0 {
+		changes = append(changes, fmt.Sprintf("stake %s -> %s", prev.Stake.VNT(), cur.Stake.VNT()))
+	}
+	if prev.LastVoteCount.Cmp(cur.LastVoteCount) != 0 {
+		changes = append(changes, fmt.Sprintf("votes %s -> %s", prev.LastVoteCount, cur.LastVoteCount))
+	}
+	if prev.ProxyVoteCount.Cmp(cur.ProxyVoteCount) != 0 {
+		changes = append(changes, fmt.Sprintf("proxy votes %s -> %s", prev.ProxyVoteCount, cur.ProxyVoteCount))
+	}
+	if proxyString(prev.Proxy) != proxyString(cur.Proxy) {
+		changes = append(changes, fmt.Sprintf("proxy %s -> %s", proxyString(prev.Proxy), proxyString(cur.Proxy)))
+	}
+	if prev.IsProxy != cur.IsProxy {
+		changes = append(changes, fmt.Sprintf("is proxy %v -> %v", prev.IsProxy, cur.IsProxy))
+	}
+	if votedString(prev.VotedCandidates) != votedString(cur.VotedCandidates) {
+		changes = append(changes, fmt.Sprintf("voted [%s] -> [%s]", votedString(prev.VotedCandidates), votedString(cur.VotedCandidates)))
+	}
+	return changes
+}
+
+func (st *watchState) alert(rule string, subject common.Address, format string, a ...interface{}) *Alert {
+	return &Alert{Rule: rule, Block: st.number, Time: st.time, Subject: subject, Message: fmt.Sprintf(format, a...)}
+}
+
+func proxyString(proxy *common.Address) string {
+	if proxy == nil {
+		return "none"
+	}
+	return proxy.String()
+}
+
+func votedString(voted []VotedCandidate) string {
+	names := make([]string, len(voted))
+	for i, vc := range voted {
+		names[i] = vc.Address.String()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,60 @@
+package elect
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	"github.com/vntchain/go-vnt/rpc"
+)
+
+func TestWatchAlerts(t *testing.T) {
+	mine := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	state := func(number uint64, votes int64, voted bool, stake int64) *watchState {
+		candidates := []rpc.Candidate{
+			testCandidate("0x0000000000000000000000000000000000000001", 100, true),
+			testCandidate("0x0000000000000000000000000000000000000002", 50, true),
+			testCandidate(mine.String(), votes, true),
+			testCandidate("0x0000000000000000000000000000000000000004", 40, true),
+		}
+		status := &Status{
+			Address:         mine,
+			ChainTime:       time.Unix(1560000000, 0),
+			Stake:           NewAmount(big.NewInt(stake)),
+			LastVoteCount:   big.NewInt(0),
+			ProxyVoteCount:  big.NewInt(0),
+			VotedCandidates: []VotedCandidate{{Address: common.HexToAddress("0x0000000000000000000000000000000000000002"), Active: voted}},
+			Candidate:       &CandidateStatus{ExtractableBounty: NewAmount(nil)},
+		}
+		return &watchState{number: number, ranking: rankCandidates(candidates, 3), status: status}
+	}
+	rules := &WatchRules{CutoffMargin: big.NewInt(5)}
+	raised := make(map[string]bool)
+	rulesOf := func(alerts []*Alert) []string {
+		var ret []string
+		for _, a := range alerts {
+			ret = append(ret, a.Rule)
+		}
+		return ret
+	}
+
+	s1 := state(1, 44, true, 10)
+	if alerts := watchAlerts(nil, s1, rules, raised); len(alerts) != 1 || alerts[0].Rule != AlertNearCutoff {
+		t.Errorf("want near_cutoff at the first check, got: %v", rulesOf(alerts))
+	}
+	s2 := state(2, 43, true, 10)
+	if alerts := watchAlerts(s1, s2, rules, raised); len(alerts) != 0 {
+		t.Errorf("want no alert raised again, got: %v", rulesOf(alerts))
+	}
+	// the voted candidate is inactive, and mine becomes the fourth
+	s3 := state(3, 30, false, 20)
+	alerts := watchAlerts(s2, s3, rules, raised)
+	if got := rulesOf(alerts); len(got) != 3 || got[0] != AlertAccountChanged || got[1] != AlertCandidateInactive || got[2] != AlertDroppedOut {
+		t.Errorf("want account_changed, candidate_inactive and dropped_out, got: %v", got)
+	}
+	rules.Disable = []string{AlertNearCutoff}
+	if alerts := watchAlerts(s3, state(4, 44, true, 20), rules, raised); len(alerts) != 0 {
+		t.Errorf("want near_cutoff disabled, got: %v", rulesOf(alerts))
+	}
+}
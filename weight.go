@@ -0,0 +1,97 @@
+package elect
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"time"
+
+	"github.com/vntchain/go-vnt/common"
+	vntelection "github.com/vntchain/go-vnt/core/vm/election"
+)
+
+// 选举合约计算票数的起始时间，2019-01-01 00:00:00 UTC+8
+const voteWeightEra = 1546272000
+
+// voteCount returns the votes of stake if voting at the time, it's calculateVoteCount of
+// the election contract: stake * 2^(weeks since 2019 / 52). The stake is the count of
+// whole VNT. It's calculated in float64 and truncated to int64 exactly as the contract
+// does, so a stake beyond uint64 or votes beyond int64 get the same wrong votes as the
+// contract instead of the right ones.
+func voteCount(stake *big.Int, at time.Time) *big.Int {
+	if stake == nil {
+		return big.NewInt(0)
+	}
+	delta := big.NewInt(at.Unix() - voteWeightEra)
+	delta.Div(delta, big.NewInt(vntelection.OneDay*7))
+	weight := float64(delta.Uint64()) / 52
+
+	votes := float64(stake.Uint64()) * math.Exp2(weight)
+	return big.NewInt(int64(votes))
+}
+
+// VoteWeight is the vote weight of an account. The votes are frozen in LastVoteCount at
+// the last vote, voting again now gets Revote, which grows every week.
+type VoteWeight struct {
+	Address       common.Address `json:"address"`
+	ChainTime     time.Time      `json:"chainTime"`     // time of the latest block
+	StakeCount    *big.Int       `json:"stakeCount"`    // stake count of the contract
+	LastVoteCount *big.Int       `json:"lastVoteCount"` // frozen votes of the last vote
+	LastVoteTime  *time.Time     `json:"lastVoteTime"`  // nil if never voted
+	Revote        *big.Int       `json:"revote"`        // votes of voting at ChainTime
+	Increase      *big.Int       `json:"increase"`      // Revote minus LastVoteCount
+}
+
+// ProjectVoteWeight returns the votes of voting with the stake at the time, it's the same
+// as the election contract. The stake is the stake count of the contract, such as StakeCount
+// of the stake information.
+func (e *Election) ProjectVoteWeight(stake *big.Int, at time.Time) *big.Int {
+	return voteCount(stake, at)
+}
+
+// QueryVoteWeight returns the vote weight of the account, or an error if failed.
+func (e *Election) QueryVoteWeight() (*VoteWeight, error) {
+	return e.QueryVoteWeightContext(e.ctx)
+}
+
+// QueryVoteWeightContext is the same as QueryVoteWeight, but uses ctx for the RPC calls.
+func (e *Election) QueryVoteWeightContext(ctx context.Context) (*VoteWeight, error) {
+	return e.QueryVoteWeightOfContext(ctx, e.cfg.Sender)
+}
+
+// QueryVoteWeightOf returns the vote weight of any account, or an error if failed.
+func (e *Election) QueryVoteWeightOf(addr common.Address) (*VoteWeight, error) {
+	return e.QueryVoteWeightOfContext(e.ctx, addr)
+}
+
+// QueryVoteWeightOfContext is the same as QueryVoteWeightOf, but uses ctx for the RPC calls.
+func (e *Election) QueryVoteWeightOfContext(ctx context.Context, addr common.Address) (*VoteWeight, error) {
+	now, err := e.chainTime(ctx)
+	if err != nil {
+		return nil, err
+	}
+	w := &VoteWeight{Address: addr, ChainTime: now, StakeCount: big.NewInt(0), LastVoteCount: big.NewInt(0)}
+
+	stake, err := e.vc.StakeAt(ctx, addr)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+	if stake != nil && stake.StakeCount != nil {
+		w.StakeCount = stake.StakeCount
+	}
+	voter, err := e.vc.VoteAt(ctx, addr)
+	if err != nil && !isNotFound(err) {
+		return nil, nodeError(err)
+	}
+	if voter != nil && voter.LastVoteTimeStamp != nil && voter.LastVoteTimeStamp.Sign() > 0 {
+		last := time.Unix(voter.LastVoteTimeStamp.Int64(), 0)
+		w.LastVoteTime = &last
+		if voter.LastVoteCount != nil {
+			w.LastVoteCount = voter.LastVoteCount
+		}
+	}
+
+	w.Revote = voteCount(w.StakeCount, now)
+	w.Increase = new(big.Int).Sub(w.Revote, w.LastVoteCount)
+	return w, nil
+}
@@ -0,0 +1,46 @@
+package elect
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestProjectVoteWeight(t *testing.T) {
+	era := time.Unix(voteWeightEra, 0)
+	week := 7 * 24 * time.Hour
+	tests := []struct {
+		stake int64
+		at    time.Time
+		want  int64
+	}{
+		{100, era, 100},
+		{100, era.Add(week - time.Second), 100}, // 按整周计算
+		{100, era.Add(26 * week), 141},          // 100 * 2^0.5，截断取整
+		{100, era.Add(52 * week), 200},
+		{100, era.Add(104 * week), 400},
+		{0, era.Add(52 * week), 0},
+	}
+
+	e := &Election{}
+	for _, test := range tests {
+		if got := e.ProjectVoteWeight(big.NewInt(test.stake), test.at); got.Int64() != test.want {
+			t.Errorf("weight of stake %d at %s want %d, got: %s", test.stake, test.at, test.want, got)
+		}
+	}
+
+	// 与合约一样使用float64计算，超出uint64的抵押数被截断
+	large := []struct {
+		stake *big.Int
+		at    time.Time
+		want  *big.Int
+	}{
+		{new(big.Int).Add(new(big.Int).Lsh(big.NewInt(1), 53), big.NewInt(1)), era, new(big.Int).Lsh(big.NewInt(1), 53)},
+		{new(big.Int).Lsh(big.NewInt(1), 64), era.Add(52 * week), big.NewInt(0)},
+	}
+	for _, test := range large {
+		if got := e.ProjectVoteWeight(test.stake, test.at); got.Cmp(test.want) != 0 {
+			t.Errorf("weight of stake %s at %s want %s, got: %s", test.stake, test.at, test.want, got)
+		}
+	}
+}